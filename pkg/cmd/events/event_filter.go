@@ -1,50 +1,315 @@
 package events
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"github.com/openshift/cluster-debug-tools/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/scheme"
 )
 
 type EventFilter interface {
 	FilterEvents(events ...*corev1.Event) []*corev1.Event
 }
 
+// ContextEventFilter is an EventFilter that stops early, returning the error of the context, once the context is done.
+// Filters that do slow work per event, like looking up the involved object, implement it.
+type ContextEventFilter interface {
+	EventFilter
+	FilterEventsCtx(ctx context.Context, events ...*corev1.Event) ([]*corev1.Event, error)
+}
+
+// FilterEventsCtx applies the filter unless the context is done, letting it stop early when it is a ContextEventFilter.
+func FilterEventsCtx(ctx context.Context, filter EventFilter, events ...*corev1.Event) ([]*corev1.Event, error) {
+	if contextFilter, ok := filter.(ContextEventFilter); ok {
+		return contextFilter.FilterEventsCtx(ctx, events...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return filter.FilterEvents(events...), nil
+}
+
+// EventPredicate judges each event on its own.  Filters that are also predicates are applied by EventFilters in a
+// single pass without building intermediate results, filters that have to see every event at once, like
+// FilterByAround or FilterByDedup, are not predicates.
+type EventPredicate interface {
+	Accept(event *corev1.Event) bool
+}
+
+// EventPredicateFunc adapts a function into an EventPredicate.
+type EventPredicateFunc func(event *corev1.Event) bool
+
+func (fn EventPredicateFunc) Accept(event *corev1.Event) bool {
+	return fn(event)
+}
+
+// PredicateFilter adapts an EventPredicate into an EventFilter.
+type PredicateFilter struct {
+	Predicate EventPredicate
+}
+
+func (f *PredicateFilter) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f.Predicate, events)
+}
+
+func (f *PredicateFilter) Accept(event *corev1.Event) bool {
+	return f.Predicate.Accept(event)
+}
+
+// allPredicates accepts the events every predicate accepts.
+type allPredicates []EventPredicate
+
+func (p allPredicates) Accept(event *corev1.Event) bool {
+	for _, predicate := range p {
+		if !predicate.Accept(event) {
+			return false
+		}
+	}
+	return true
+}
+
+func filterByPredicate(predicate EventPredicate, events []*corev1.Event) []*corev1.Event {
+	ret, _ := filterByPredicateCtx(context.Background(), predicate, events)
+	return ret
+}
+
+// predicateContextInterval is how many events are judged between checks of the context
+const predicateContextInterval = 1024
+
+func filterByPredicateCtx(ctx context.Context, predicate EventPredicate, events []*corev1.Event) ([]*corev1.Event, error) {
+	ret := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		if i%predicateContextInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		event := events[i]
+		if predicate.Accept(event) {
+			ret = append(ret, event)
+		}
+	}
+
+	return ret, nil
+}
+
+// EventFilters matches the events matched by all of its filters, applied in order until no event is left.
 type EventFilters []EventFilter
 
 func (f EventFilters) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	ret, _ := f.FilterEventsCtx(context.Background(), events...)
+	return ret
+}
+
+func (f EventFilters) FilterEventsCtx(ctx context.Context, events ...*corev1.Event) ([]*corev1.Event, error) {
 	ret := make([]*corev1.Event, len(events), len(events))
 	copy(ret, events)
 
-	for _, filter := range f {
-		ret = filter.FilterEvents(ret...)
+	for i := 0; i < len(f); {
+		// once no event is left the remaining filters have nothing to do, and some, like FilterByAround, need events
+		if len(ret) == 0 {
+			return ret, nil
+		}
+
+		// consecutive predicates are applied in a single pass
+		predicates := allPredicates{}
+		for ; i < len(f); i++ {
+			predicate, ok := f[i].(EventPredicate)
+			if !ok {
+				break
+			}
+			predicates = append(predicates, predicate)
+		}
+		if len(predicates) > 0 {
+			var err error
+			if ret, err = filterByPredicateCtx(ctx, predicates, ret); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var err error
+		if ret, err = FilterEventsCtx(ctx, f[i], ret...); err != nil {
+			return nil, err
+		}
+		i++
+	}
+
+	return ret, nil
+}
+
+// AnyFilter matches events matched by any of its filters, where EventFilters matches events matched by all of them.
+// Each event is returned at most once, in the order the filters matched it.
+type AnyFilter struct {
+	Filters []EventFilter
+}
+
+func (f *AnyFilter) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	inputs := map[*corev1.Event]bool{}
+	for _, event := range events {
+		inputs[event] = true
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	seen := map[*corev1.Event]bool{}
+	seenIDs := sets.NewString()
+	for _, filter := range f.Filters {
+		for _, event := range filter.FilterEvents(events...) {
+			if seen[event] {
+				continue
+			}
+			seen[event] = true
+
+			// filters that return copies rather than the events passed in are deduplicated by identity
+			if !inputs[event] && len(event.UID) > 0 {
+				id := string(event.UID) + "/" + event.ResourceVersion
+				if seenIDs.Has(id) {
+					continue
+				}
+				seenIDs.Insert(id)
+			}
+			ret = append(ret, event)
+		}
 	}
 
 	return ret
 }
 
-type FilterByWarnings struct {
+// NotFilter matches the events its Delegate rejects, in their original order.
+type NotFilter struct {
+	Delegate EventFilter
 }
 
-func (f *FilterByWarnings) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
+func (f *NotFilter) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	ret, _ := f.FilterEventsCtx(context.Background(), events...)
+	return ret
+}
+
+// FilterEventsCtx returns the error of the Delegate rather than every event, which it would have matched none of.
+func (f *NotFilter) FilterEventsCtx(ctx context.Context, events ...*corev1.Event) ([]*corev1.Event, error) {
+	delegated, err := FilterEventsCtx(ctx, f.Delegate, events...)
+	if err != nil {
+		return nil, err
+	}
+	accepted := map[*corev1.Event]bool{}
+	for _, event := range delegated {
+		accepted[event] = true
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
 	for i := range events {
 		event := events[i]
-		if event.Type != corev1.EventTypeWarning {
+		if accepted[event] {
 			continue
 		}
 		ret = append(ret, event)
 	}
 
-	return ret
+	return ret, nil
+}
+
+// FilterByType matches events by type, Normal or Warning, with the usual "-" exclusions and "*" wildcards.
+type FilterByType struct {
+	Types sets.String
+}
+
+func (f *FilterByType) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByType) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.Types, event.Type)
+}
+
+func (f *FilterByType) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "type", unmatchedValues(f.Types, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.Type}
+	})
+}
+
+// FilterByWarnings matches Warning events, the same as a FilterByType of Warning.
+type FilterByWarnings struct {
+}
+
+func (f *FilterByWarnings) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByWarnings) Accept(event *corev1.Event) bool {
+	return event.Type == corev1.EventTypeWarning
+}
+
+// FilterByClusterScoped matches events about cluster-scoped objects, like nodes and persistent volumes, whose involved
+// object has no namespace.  With Namespaced set it matches the other events instead.  Chained with FilterByNamespaces
+// an event has to match both, so a namespace other than "" leaves no cluster-scoped events.
+type FilterByClusterScoped struct {
+	Namespaced bool
+}
+
+func (f *FilterByClusterScoped) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByClusterScoped) Accept(event *corev1.Event) bool {
+	return (len(event.InvolvedObject.Namespace) == 0) != f.Namespaced
+}
+
+// DefaultBenignReasons are the reasons of the Normal events of every healthy pod and deployment, which
+// NewFilterByBenign drops unless given other reasons.
+var DefaultBenignReasons = []string{
+	"Scheduled",
+	"Pulling",
+	"Pulled",
+	"Created",
+	"Started",
+	"SuccessfulCreate",
+	"SuccessfulDelete",
+	"ScalingReplicaSet",
+	"LeaderElection",
+}
+
+// FilterByBenign drops the Normal events with one of the Reasons to declutter the output.  Warning events are kept
+// whatever their reason, so it complements FilterByWarnings rather than repeating it.
+type FilterByBenign struct {
+	Reasons sets.String
+}
+
+// NewFilterByBenign drops the Normal events with one of the reasons, DefaultBenignReasons when none are given.
+func NewFilterByBenign(reasons []string) *FilterByBenign {
+	if len(reasons) == 0 {
+		reasons = DefaultBenignReasons
+	}
+	return &FilterByBenign{Reasons: sets.NewString(reasons...)}
+}
+
+func (f *FilterByBenign) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByBenign) Accept(event *corev1.Event) bool {
+	if event.Type != corev1.EventTypeNormal {
+		return true
+	}
+	return !f.Reasons.Has(event.Reason)
 }
 
 type FilterByNamespaces struct {
@@ -52,16 +317,83 @@ type FilterByNamespaces struct {
 }
 
 func (f *FilterByNamespaces) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
-	for i := range events {
-		event := events[i]
+	return filterByPredicate(f, events)
+}
 
-		if util.AcceptString(f.Namespaces, event.InvolvedObject.Namespace) {
-			ret = append(ret, event)
+func (f *FilterByNamespaces) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.Namespaces, event.InvolvedObject.Namespace)
+}
+
+func (f *FilterByNamespaces) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "namespace", unmatchedValues(f.Namespaces, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.InvolvedObject.Namespace}
+	})
+}
+
+// FilterByNamespaceRegex matches events whose involved object namespace matches any of the regular expressions, for
+// namespaces named dynamically like e2e-test-build-1234.  The expressions are unanchored, ^$ matches cluster-scoped
+// objects.
+type FilterByNamespaceRegex struct {
+	Patterns []*regexp.Regexp
+}
+
+// NewFilterByNamespaceRegex compiles the patterns up front so an invalid one is reported before filtering.
+func NewFilterByNamespaceRegex(patterns []string) (*FilterByNamespaceRegex, error) {
+	f := &FilterByNamespaceRegex{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace pattern %q: %v", pattern, err)
 		}
+		f.Patterns = append(f.Patterns, re)
 	}
+	return f, nil
+}
 
-	return ret
+func (f *FilterByNamespaceRegex) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByNamespaceRegex) Accept(event *corev1.Event) bool {
+	for _, re := range f.Patterns {
+		if re.MatchString(event.InvolvedObject.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByNameRegex matches events whose involved object name matches any of the regular expressions, for generated
+// names like web-7d8f9-abcde.  The expressions are unanchored, ^web- matches only names starting with web-.  Exact
+// names and exclusions are left to FilterByNames.
+type FilterByNameRegex struct {
+	Patterns []*regexp.Regexp
+}
+
+// NewFilterByNameRegex compiles the patterns up front so an invalid one is reported before filtering.
+func NewFilterByNameRegex(patterns []string) (*FilterByNameRegex, error) {
+	f := &FilterByNameRegex{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %v", pattern, err)
+		}
+		f.Patterns = append(f.Patterns, re)
+	}
+	return f, nil
+}
+
+func (f *FilterByNameRegex) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByNameRegex) Accept(event *corev1.Event) bool {
+	for _, re := range f.Patterns {
+		if re.MatchString(event.InvolvedObject.Name) {
+			return true
+		}
+	}
+	return false
 }
 
 type FilterByNames struct {
@@ -69,165 +401,1255 @@ type FilterByNames struct {
 }
 
 func (f *FilterByNames) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
-	for i := range events {
-		event := events[i]
+	return filterByPredicate(f, events)
+}
 
-		if util.AcceptString(f.Names, event.InvolvedObject.Name) {
-			ret = append(ret, event)
-		}
-	}
+func (f *FilterByNames) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.Names, event.InvolvedObject.Name)
+}
 
-	return ret
+func (f *FilterByNames) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "name", unmatchedValues(f.Names, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.InvolvedObject.Name}
+	})
 }
 
+// FilterByReasons matches events by reason, ignoring case when IgnoreCase is set.  When Prefix is set every value,
+// exclusions included, also matches the reasons it is a prefix of, so Failed matches FailedScheduling and FailedMount
+// while -FailedMount still excludes the latter.  Use NewFilterByReasons to compare the reasons ignoring case or as
+// prefixes, a FilterByReasons built otherwise prepares them again for every event.
 type FilterByReasons struct {
-	Reasons sets.String
+	Reasons    sets.String
+	IgnoreCase bool
+	Prefix     bool
+
+	// acceptedReasons are the Reasons as compared, in lower case and as prefixes when set
+	acceptedReasons sets.String
 }
 
-func (f *FilterByReasons) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
-	for i := range events {
-		event := events[i]
+// NewFilterByReasons prepares the reasons to compare once, the filter is not modified when filtering so it may be
+// shared.
+func NewFilterByReasons(reasons []string, ignoreCase, prefix bool) *FilterByReasons {
+	f := &FilterByReasons{Reasons: sets.NewString(reasons...), IgnoreCase: ignoreCase, Prefix: prefix}
+	f.acceptedReasons = f.compared()
+	return f
+}
 
-		if util.AcceptString(f.Reasons, event.Reason) {
-			ret = append(ret, event)
+// compared returns the Reasons as compared, in lower case and as prefixes when set.
+func (f *FilterByReasons) compared() sets.String {
+	ret := f.Reasons
+	if f.IgnoreCase {
+		ret = util.LowerStrings(ret)
+	}
+	if f.Prefix {
+		prefixes := sets.NewString()
+		for _, reason := range ret.UnsortedList() {
+			prefixes.Insert(strings.TrimSuffix(reason, "*") + "*")
 		}
+		ret = prefixes
 	}
-
 	return ret
 }
 
-type FilterByAround struct {
-	Around         string
-	AroundDuration time.Duration
+func (f *FilterByReasons) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
 }
 
-func (f *FilterByAround) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	t := events[len(events)-1].LastTimestamp.Time
-	aroundParts := strings.Split(f.Around, ":")
-	if len(aroundParts) < 2 || len(aroundParts) > 3 {
-		fmt.Fprintf(os.Stderr, "invalid around time format, must be HH:MM or HH:MM:SS, got %q", f.Around)
-		return nil
+func (f *FilterByReasons) Accept(event *corev1.Event) bool {
+	if !f.IgnoreCase && !f.Prefix {
+		return util.AcceptString(f.Reasons, event.Reason)
 	}
-	aroundTimeHours, err := strconv.Atoi(aroundParts[0])
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error parsing around time: %b", err)
-		return nil
+	reasons := f.acceptedReasons
+	if reasons == nil {
+		reasons = f.compared()
 	}
-	aroundTimeMinutes, err := strconv.Atoi(aroundParts[1])
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error parsing around time: %b", err)
-		return nil
+	reason := event.Reason
+	if f.IgnoreCase {
+		reason = strings.ToLower(reason)
 	}
-	aroundTimeSeconds := 0
-	if len(aroundParts) > 2 {
-		aroundTimeSeconds, err = strconv.Atoi(aroundParts[2])
+	return util.AcceptString(reasons, reason)
+}
+
+func (f *FilterByReasons) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "reason", unmatchedValues(f.Reasons, events, f.IgnoreCase, f.Prefix, func(event *corev1.Event) []string {
+		return []string{event.Reason}
+	})
+}
+
+// FilterByAction matches events by the action the reporting controller took, or failed to take, like Binding or
+// Scheduling.  Events of the events.k8s.io API read as core events keep Action as is, while their Note becomes the
+// Message, Regarding becomes the InvolvedObject and DeprecatedSource becomes the Source, so the other filters apply to
+// them unchanged.  Events recorded through the core API often leave Action empty.
+type FilterByAction struct {
+	Actions sets.String
+}
+
+func (f *FilterByAction) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByAction) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.Actions, event.Action)
+}
+
+func (f *FilterByAction) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "action", unmatchedValues(f.Actions, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.Action}
+	})
+}
+
+// FilterByMessage matches events by their message.  By default each value is a case-insensitive substring, when
+// Regexp is set each value is an unanchored regular expression, matched case-insensitively if IgnoreCase is set.
+// Values prefixed with "-" exclude the events they match.
+type FilterByMessage struct {
+	Messages   sets.String
+	Regexp     bool
+	IgnoreCase bool
+
+	matches     []*regexp.Regexp
+	antiMatches []*regexp.Regexp
+}
+
+func NewFilterByMessage(messages []string, useRegexp, ignoreCase bool) (*FilterByMessage, error) {
+	f := &FilterByMessage{Messages: sets.NewString(messages...), Regexp: useRegexp, IgnoreCase: ignoreCase}
+	for _, message := range f.Messages.List() {
+		antiMatch := strings.HasPrefix(message, "-")
+		if antiMatch {
+			message = message[1:]
+		}
+		pattern := message
+		switch {
+		case !useRegexp:
+			pattern = "(?i)" + regexp.QuoteMeta(message)
+		case ignoreCase:
+			pattern = "(?i)" + message
+		}
+		re, err := regexp.Compile(pattern)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "error parsing around time: %b", err)
-			return nil
+			return nil, fmt.Errorf("invalid message pattern %q: %v", message, err)
+		}
+		if antiMatch {
+			f.antiMatches = append(f.antiMatches, re)
+		} else {
+			f.matches = append(f.matches, re)
 		}
 	}
 
-	aroundTime := time.Date(t.Year(), t.Month(), t.Day(), aroundTimeHours, aroundTimeMinutes, aroundTimeSeconds, t.Nanosecond(), t.Location())
-	ret := []*corev1.Event{}
-	for i := range events {
-		event := events[i]
-		if event.LastTimestamp.Time.After(aroundTime.Add(f.AroundDuration)) || event.LastTimestamp.Time.Before(aroundTime.Add(-f.AroundDuration)) {
-			continue
+	return f, nil
+}
+
+func (f *FilterByMessage) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByMessage) Accept(event *corev1.Event) bool {
+	// check for an anti-match
+	for _, re := range f.antiMatches {
+		if re.MatchString(event.Message) {
+			return false
+		}
+	}
+	// if all values are negation, assume * by default
+	if len(f.matches) == 0 {
+		return true
+	}
+	for _, re := range f.matches {
+		if re.MatchString(event.Message) {
+			return true
 		}
-		ret = append(ret, event)
 	}
 
-	return ret
+	return false
 }
 
-type FilterByUIDs struct {
-	UIDs sets.String
+// FilterByCount matches events that were observed at least MinCount times and, when MaxCount is set, at most MaxCount
+// times.  Events without a count were observed once.
+type FilterByCount struct {
+	MinCount int32
+	MaxCount int32
 }
 
-func (f *FilterByUIDs) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
-	for i := range events {
-		event := events[i]
+func (f *FilterByCount) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
 
-		if util.AcceptString(f.UIDs, string(event.InvolvedObject.UID)) {
-			ret = append(ret, event)
-		}
+func (f *FilterByCount) Accept(event *corev1.Event) bool {
+	count := int32(eventCount(event))
+	if count < f.MinCount {
+		return false
 	}
+	if f.MaxCount > 0 && count > f.MaxCount {
+		return false
+	}
+	return true
+}
 
-	return ret
+// FilterByMessageLength matches events whose message is at least MinLen and, when MaxLen is set, at most MaxLen bytes
+// long, to hide the events that only echo their reason or to find the verbose ones.  Both bounds are inclusive, an
+// empty message has a length of zero.
+type FilterByMessageLength struct {
+	MinLen int
+	MaxLen int
 }
 
-type FilterByComponent struct {
-	Components sets.String
+func (f *FilterByMessageLength) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
 }
 
-func (f *FilterByComponent) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
+func (f *FilterByMessageLength) Accept(event *corev1.Event) bool {
+	length := len(event.Message)
+	if length < f.MinLen {
+		return false
+	}
+	if f.MaxLen > 0 && length > f.MaxLen {
+		return false
+	}
+	return true
+}
+
+// FilterByDedup collapses events about the same object with the same reason and message into one event, spanning the
+// earliest FirstTimestamp to the latest LastTimestamp with the summed Count.  The events passed in are not modified.
+type FilterByDedup struct {
+}
+
+func (f *FilterByDedup) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	ret := make([]*corev1.Event, 0, len(events))
+	collapsed := map[string]*corev1.Event{}
 	for i := range events {
 		event := events[i]
+		key := strings.Join([]string{string(event.InvolvedObject.UID), event.Reason, event.Message}, "/")
 
-		if util.AcceptString(f.Components, event.ReportingController) {
-			ret = append(ret, event)
+		existing, ok := collapsed[key]
+		if !ok {
+			existing = event.DeepCopy()
+			if existing.Count == 0 {
+				existing.Count = 1
+			}
+			collapsed[key] = existing
+			ret = append(ret, existing)
+			continue
+		}
+
+		if event.Count == 0 {
+			existing.Count++
+		} else {
+			existing.Count += event.Count
+		}
+		if !event.FirstTimestamp.IsZero() && (existing.FirstTimestamp.IsZero() || event.FirstTimestamp.Before(&existing.FirstTimestamp)) {
+			existing.FirstTimestamp = event.FirstTimestamp
+		}
+		if existing.LastTimestamp.Before(&event.LastTimestamp) {
+			existing.LastTimestamp = event.LastTimestamp
 		}
 	}
 
 	return ret
 }
 
-type FilterByKind struct {
-	Kinds map[schema.GroupKind]bool
+// FilterByFlapping matches events that recur over a long window, observed at least MinCount times between a
+// FirstTimestamp and LastTimestamp at least MinSpan apart.  These slow-burn events are easily missed by a count
+// threshold alone.  Events without both timestamps, or with equal ones, never match.
+type FilterByFlapping struct {
+	MinSpan  time.Duration
+	MinCount int32
 }
 
-func (f *FilterByKind) FilterEvents(events ...*corev1.Event) []*corev1.Event {
-	ret := []*corev1.Event{}
-	for i := range events {
-		event := events[i]
-		gv, _ := schema.ParseGroupVersion(event.InvolvedObject.APIVersion)
-		gk := gv.WithKind(event.InvolvedObject.Kind).GroupKind()
-		antiMatch := schema.GroupKind{Kind: "-" + gk.Kind, Group: gk.Group}
+func (f *FilterByFlapping) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
 
-		// check for an anti-match
-		if f.Kinds[antiMatch] {
-			continue
+func (f *FilterByFlapping) Accept(event *corev1.Event) bool {
+	span := EventSpan(event)
+	if span == 0 || span < f.MinSpan {
+		return false
+	}
+	return int32(eventCount(event)) >= f.MinCount
+}
+
+// FilterByRate matches the events of the involved objects and reasons that fire more than MinRatePerMinute times a
+// minute, which tells a burst from a slow trickle with the same count.  The rate of an object and reason is the summed
+// count of its events over the time from the first to the last observation of any of them.  Repeated events observed
+// within the same instant fire at an unbounded rate, an event observed once has no rate and never matches.
+type FilterByRate struct {
+	MinRatePerMinute float64
+}
+
+func (f *FilterByRate) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	type rateKey struct {
+		object string
+		uid    types.UID
+		reason string
+	}
+	type observations struct {
+		count       int
+		first, last time.Time
+	}
+
+	groups := map[rateKey]*observations{}
+	keys := make([]rateKey, 0, len(events))
+	for _, event := range events {
+		key := rateKey{object: involvedObjectKey(event.InvolvedObject), uid: event.InvolvedObject.UID, reason: event.Reason}
+		keys = append(keys, key)
+		group, ok := groups[key]
+		if !ok {
+			group = &observations{}
+			groups[key] = group
 		}
-		if f.Kinds[gk] {
-			ret = append(ret, event)
+		group.count += eventCount(event)
+		if first := firstObserved(event); !first.IsZero() && (group.first.IsZero() || first.Before(group.first)) {
+			group.first = first
 		}
-
-		// if we aren't an exact match, match on resource only if group is '*'
-		// check for an anti-match
-		antiMatched := false
-		for currKind := range f.Kinds {
-			if currKind.Group == "*" && currKind.Kind == antiMatch.Kind {
-				antiMatched = true
-				break
-			}
-			if currKind.Kind == "-*" && currKind.Group == gk.Group {
-				antiMatched = true
-				break
-			}
+		if last := lastObserved(event); last.After(group.last) {
+			group.last = last
 		}
-		if antiMatched {
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	for i, event := range events {
+		group := groups[keys[i]]
+		if group.count < 2 {
 			continue
 		}
-
-		for currResource := range f.Kinds {
-			if currResource.Group == "*" && currResource.Kind == "*" {
-				ret = append(ret, event)
-				break
-			}
-			if currResource.Group == "*" && currResource.Kind == gk.Kind {
-				ret = append(ret, event)
-				break
-			}
-			if currResource.Kind == "*" && currResource.Group == gk.Group {
-				ret = append(ret, event)
-				break
-			}
+		rate := math.Inf(1)
+		if span := group.last.Sub(group.first); span > 0 {
+			rate = float64(group.count) / span.Minutes()
+		}
+		if rate > f.MinRatePerMinute {
+			ret = append(ret, event)
 		}
 	}
 
 	return ret
 }
+
+// FilterByObjectSpread matches the events whose reason is reported about at least MinObjects distinct involved objects,
+// like FailedMount on many pods pointing at storage, rather than about one object over and over.  Objects are told
+// apart by namespace, kind, name and UID.
+type FilterByObjectSpread struct {
+	MinObjects int
+}
+
+func (f *FilterByObjectSpread) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	objects := map[string]sets.String{}
+	for _, event := range events {
+		if objects[event.Reason] == nil {
+			objects[event.Reason] = sets.NewString()
+		}
+		objects[event.Reason].Insert(involvedObjectKey(event.InvolvedObject) + "/" + string(event.InvolvedObject.UID))
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		event := events[i]
+		if objects[event.Reason].Len() >= f.MinObjects {
+			ret = append(ret, event)
+		}
+	}
+
+	return ret
+}
+
+// DedupEvents collapses the copies of an event read from more than one source, like the live cluster and a snapshot,
+// which share the involved object, reason, message and source.  Of each set of copies it keeps the one with the highest
+// Count, then the latest LastTimestamp, at the position of the first copy.  Unlike FilterByDedup the counts are not
+// summed, because every copy records the same occurrences.
+func DedupEvents(events []*corev1.Event) []*corev1.Event {
+	type dedupKey struct {
+		object    string
+		uid       types.UID
+		reason    string
+		message   string
+		component string
+		host      string
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	index := map[dedupKey]int{}
+	for _, event := range events {
+		key := dedupKey{
+			object:    involvedObjectKey(event.InvolvedObject),
+			uid:       event.InvolvedObject.UID,
+			reason:    event.Reason,
+			message:   event.Message,
+			component: eventComponent(event),
+			host:      event.Source.Host,
+		}
+		if len(key.host) == 0 {
+			key.host = event.ReportingInstance
+		}
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(ret)
+			ret = append(ret, event)
+			continue
+		}
+
+		existing := ret[i]
+		if event.Count > existing.Count || (event.Count == existing.Count && existing.LastTimestamp.Before(&event.LastTimestamp)) {
+			ret[i] = event
+		}
+	}
+
+	return ret
+}
+
+// FilterByAround matches events whose EventTimestamp is within AroundDuration of the around time, or of any of the
+// Arounds times, so the events around several spikes can be found in one pass.  An HH:MM[:SS] around time is taken on
+// the day of the most recent event, regardless of the order the events are passed in.  Around times that do not parse
+// match nothing, NewFilterByAround and NewFilterByArounds validate them.
+type FilterByAround struct {
+	Around string
+	// Arounds are more around times, each may carry its own duration as <time>/<duration>, e.g. "15:30/5m"
+	Arounds        []string
+	AroundDuration time.Duration
+	// Location is the time zone an HH:MM[:SS] around time is given in, defaults to UTC.
+	Location *time.Location
+	// Clock tells the current time for around times relative to now, defaults to the RealClock
+	Clock Clock
+
+	// anchors are parsed from the around times in parsed, they are parsed again when Around or Arounds no longer match
+	anchors []aroundAnchor
+	parsed  []string
+}
+
+// aroundAnchor is a parsed around time.
+type aroundAnchor struct {
+	clock clockTime
+
+	// relative is set when the around time is an offset from now, e.g. "now-15m"
+	relative bool
+	offset   time.Duration
+
+	// duration overrides the AroundDuration of the filter when set
+	duration time.Duration
+}
+
+// NewFilterByAround validates the around time, which must be in HH:MM or HH:MM:SS format, or relative to the current
+// time as "now" or "now-<duration>" (e.g. "now-15m").
+func NewFilterByAround(around string, aroundDuration time.Duration) (*FilterByAround, error) {
+	f, err := NewFilterByArounds([]string{around}, aroundDuration)
+	if err != nil {
+		return nil, err
+	}
+	f.Around, f.Arounds = around, nil
+	return f, nil
+}
+
+// NewFilterByArounds validates several around times, in the format of NewFilterByAround and optionally followed by
+// /<duration> to use instead of aroundDuration (e.g. "14:05" and "15:30/5m").  Events around any of them match.
+func NewFilterByArounds(arounds []string, aroundDuration time.Duration) (*FilterByAround, error) {
+	f := &FilterByAround{Arounds: arounds, AroundDuration: aroundDuration}
+	for _, around := range arounds {
+		anchor, err := parseAroundAnchor(around)
+		if err != nil {
+			return nil, err
+		}
+		f.anchors = append(f.anchors, anchor)
+	}
+	f.parsed = f.aroundTimes()
+	return f, nil
+}
+
+// aroundTimes returns Around, when set, followed by the Arounds.
+func (f *FilterByAround) aroundTimes() []string {
+	ret := []string{}
+	if len(f.Around) > 0 {
+		ret = append(ret, f.Around)
+	}
+	return append(ret, f.Arounds...)
+}
+
+// aroundAnchors returns the parsed around times, parsing them again without modifying the filter when it was not
+// built by NewFilterByArounds or its around times changed since.
+func (f *FilterByAround) aroundAnchors() []aroundAnchor {
+	arounds := f.aroundTimes()
+	if len(arounds) == len(f.parsed) {
+		changed := false
+		for i := range arounds {
+			changed = changed || arounds[i] != f.parsed[i]
+		}
+		if !changed {
+			return f.anchors
+		}
+	}
+	ret := make([]aroundAnchor, 0, len(arounds))
+	for _, around := range arounds {
+		anchor, err := parseAroundAnchor(around)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, anchor)
+	}
+	return ret
+}
+
+func parseAroundAnchor(around string) (aroundAnchor, error) {
+	anchor := aroundAnchor{}
+	value := around
+	if i := strings.LastIndex(around, "/"); i >= 0 {
+		duration, err := time.ParseDuration(around[i+1:])
+		if err != nil {
+			return anchor, fmt.Errorf("error parsing duration of around time %q: %v", around, err)
+		}
+		if duration <= 0 {
+			return anchor, fmt.Errorf("duration of around time %q must be positive", around)
+		}
+		anchor.duration = duration
+		value = around[:i]
+	}
+
+	if value == "now" {
+		anchor.relative = true
+		return anchor, nil
+	}
+	if strings.HasPrefix(value, "now-") {
+		offset, err := time.ParseDuration(strings.TrimPrefix(value, "now-"))
+		if err != nil {
+			return anchor, fmt.Errorf("error parsing around time %q: %v", around, err)
+		}
+		anchor.relative = true
+		anchor.offset = offset
+		return anchor, nil
+	}
+
+	clock, err := parseClockTime(value)
+	if err != nil {
+		return anchor, fmt.Errorf("invalid around time: %v", err)
+	}
+	anchor.clock = clock
+
+	return anchor, nil
+}
+
+func (f *FilterByAround) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	// an earlier filter may have removed every event, leaving nothing to anchor on
+	if len(events) == 0 {
+		return []*corev1.Event{}
+	}
+
+	// the windows may overlap, every event is still only checked, and kept, once
+	type window struct{ start, end time.Time }
+	anchors := f.aroundAnchors()
+	windows := make([]window, 0, len(anchors))
+	location := f.Location
+	if location == nil {
+		location = time.UTC
+	}
+	latest := latestTimestamp(events).In(location)
+	now := clockOrReal(f.Clock).Now()
+	for _, anchor := range anchors {
+		aroundTime := anchor.clock.on(latest)
+		if anchor.relative {
+			aroundTime = now.Add(-anchor.offset)
+		}
+		duration := f.AroundDuration
+		if anchor.duration > 0 {
+			duration = anchor.duration
+		}
+		windows = append(windows, window{start: aroundTime.Add(-duration), end: aroundTime.Add(duration)})
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		event := events[i]
+		t := EventTimestamp(event)
+		for _, w := range windows {
+			if !t.After(w.end) && !t.Before(w.start) {
+				ret = append(ret, event)
+				break
+			}
+		}
+	}
+
+	return ret
+}
+
+// FilterByTimeRange matches events whose EventTimestamp is between Start and End, both inclusive.  A zero Start or End
+// leaves that side of the range open.
+type FilterByTimeRange struct {
+	Start time.Time
+	End   time.Time
+
+	// startClock and endClock are HH:MM[:SS] times resolved against the day of the most recent event
+	startClock *clockTime
+	endClock   *clockTime
+}
+
+// NewFilterByTimeRange parses since and until as RFC3339 or HH:MM[:SS] times, either may be empty to leave the range
+// open on that side.
+func NewFilterByTimeRange(since, until string) (*FilterByTimeRange, error) {
+	f := &FilterByTimeRange{}
+	var err error
+	if f.Start, f.startClock, err = parseTimeOrClockTime(since); err != nil {
+		return nil, fmt.Errorf("invalid since time: %v", err)
+	}
+	if f.End, f.endClock, err = parseTimeOrClockTime(until); err != nil {
+		return nil, fmt.Errorf("invalid until time: %v", err)
+	}
+
+	// a range mixing a time of day with an absolute time can only be checked once the day is known
+	switch {
+	case !f.Start.IsZero() && !f.End.IsZero() && f.Start.After(f.End):
+		return nil, fmt.Errorf("since time %q is after until time %q", since, until)
+	case f.startClock != nil && f.endClock != nil && f.endClock.before(*f.startClock):
+		return nil, fmt.Errorf("since time %q is after until time %q", since, until)
+	}
+	return f, nil
+}
+
+func (f *FilterByTimeRange) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	if len(events) == 0 {
+		return []*corev1.Event{}
+	}
+
+	start, end := f.Start, f.End
+	if f.startClock != nil {
+		start = f.startClock.on(latestTimestamp(events))
+	}
+	if f.endClock != nil {
+		end = f.endClock.on(latestTimestamp(events))
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		event := events[i]
+		t := EventTimestamp(event)
+		if !start.IsZero() && t.Before(start) {
+			continue
+		}
+		if !end.IsZero() && t.After(end) {
+			continue
+		}
+		ret = append(ret, event)
+	}
+
+	return ret
+}
+
+// FilterByAge matches events whose EventTimestamp is no more than MaxAge and, when MinAge is set, at least MinAge ago.
+// Events without any timestamp never match.
+type FilterByAge struct {
+	MaxAge time.Duration
+	MinAge time.Duration
+
+	// FromNewest ages the events relative to the EventTimestamp of the newest event passed in instead of the current
+	// time, so filtering a captured dump gives the same result on every run.
+	FromNewest bool
+
+	// Clock tells the current time, defaults to the RealClock
+	Clock Clock
+}
+
+func (f *FilterByAge) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	var currTime time.Time
+	if f.FromNewest {
+		currTime = latestTimestamp(events)
+	} else {
+		currTime = clockOrReal(f.Clock).Now()
+	}
+
+	ret := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		event := events[i]
+		t := EventTimestamp(event)
+		if t.IsZero() {
+			continue
+		}
+		age := currTime.Sub(t)
+		if f.MaxAge > 0 && age > f.MaxAge {
+			continue
+		}
+		if f.MinAge > 0 && age < f.MinAge {
+			continue
+		}
+		ret = append(ret, event)
+	}
+
+	return ret
+}
+
+// FilterBySince matches events whose EventTimestamp is within Since of the current time, like the --since of kubectl
+// logs.  A zero Since matches every event.
+type FilterBySince struct {
+	Since time.Duration
+
+	// Clock tells the current time, defaults to the RealClock
+	Clock Clock
+}
+
+func (f *FilterBySince) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	if f.Since <= 0 {
+		return events
+	}
+	return (&FilterByAge{MaxAge: f.Since, Clock: f.Clock}).FilterEvents(events...)
+}
+
+// FilterByResourceVersion matches events by the resource version of the involved object they were recorded against,
+// to follow what a controller saw of one generation of an object.
+type FilterByResourceVersion struct {
+	ResourceVersions sets.String
+}
+
+func (f *FilterByResourceVersion) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByResourceVersion) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.ResourceVersions, event.InvolvedObject.ResourceVersion)
+}
+
+func (f *FilterByResourceVersion) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "resource-version", unmatchedValues(f.ResourceVersions, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.InvolvedObject.ResourceVersion}
+	})
+}
+
+// FilterByResourceVersionRange matches events whose involved object resource version, read as a number, is at least Min
+// and at most Max, to slice events by the etcd revision they were recorded at.  A zero bound leaves that side open.
+// Resource versions are opaque to clients, so ones that are not numbers, or empty, never match once a bound is set.
+type FilterByResourceVersionRange struct {
+	Min uint64
+	Max uint64
+}
+
+func (f *FilterByResourceVersionRange) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByResourceVersionRange) Accept(event *corev1.Event) bool {
+	if f.Min == 0 && f.Max == 0 {
+		return true
+	}
+	resourceVersion, err := strconv.ParseUint(event.InvolvedObject.ResourceVersion, 10, 64)
+	if err != nil {
+		return false
+	}
+	if resourceVersion < f.Min {
+		return false
+	}
+	if f.Max > 0 && resourceVersion > f.Max {
+		return false
+	}
+	return true
+}
+
+// FilterByUIDs matches events by the UID of the involved object, selecting every event about an object.
+type FilterByUIDs struct {
+	UIDs sets.String
+}
+
+func (f *FilterByUIDs) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByUIDs) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.UIDs, string(event.InvolvedObject.UID))
+}
+
+func (f *FilterByUIDs) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "uid", unmatchedValues(f.UIDs, events, false, false, func(event *corev1.Event) []string {
+		return []string{string(event.InvolvedObject.UID)}
+	})
+}
+
+// FilterByEventUIDs matches events by the UID of the event itself, selecting specific events.
+type FilterByEventUIDs struct {
+	UIDs sets.String
+}
+
+func (f *FilterByEventUIDs) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByEventUIDs) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.UIDs, string(event.UID))
+}
+
+func (f *FilterByEventUIDs) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "event-uid", unmatchedValues(f.UIDs, events, false, false, func(event *corev1.Event) []string {
+		return []string{string(event.UID)}
+	})
+}
+
+// FilterByRelated matches events by the object they relate the involved object to, like the ReplicaSet of a Pod.
+// Empty sets match any value, events without a related object never match.
+type FilterByRelated struct {
+	Kinds      sets.String
+	Names      sets.String
+	Namespaces sets.String
+}
+
+func (f *FilterByRelated) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByRelated) Accept(event *corev1.Event) bool {
+	if event.Related == nil {
+		return false
+	}
+	if len(f.Kinds) > 0 && !util.AcceptString(f.Kinds, event.Related.Kind) {
+		return false
+	}
+	if len(f.Names) > 0 && !util.AcceptString(f.Names, event.Related.Name) {
+		return false
+	}
+	if len(f.Namespaces) > 0 && !util.AcceptString(f.Namespaces, event.Related.Namespace) {
+		return false
+	}
+	return true
+}
+
+// FilterByComponent matches events by ReportingController or the legacy Source.Component, since older events, such
+// as those emitted by the kubelet, only populate the latter and newer ones may populate both.  An event matches when
+// either field is included and neither is excluded.
+type FilterByComponent struct {
+	Components sets.String
+}
+
+func (f *FilterByComponent) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByComponent) Accept(event *corev1.Event) bool {
+	return util.AcceptAnyString(f.Components, event.ReportingController, event.Source.Component)
+}
+
+func (f *FilterByComponent) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "component", unmatchedValues(f.Components, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.ReportingController, event.Source.Component}
+	})
+}
+
+// FilterByReportingInstance matches events by ReportingInstance alone, which tells the replicas of a controller apart,
+// like the scheduler leader that emitted an event.  FilterByHosts also matches the legacy Source.Host.
+type FilterByReportingInstance struct {
+	Instances sets.String
+}
+
+func (f *FilterByReportingInstance) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByReportingInstance) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.Instances, event.ReportingInstance)
+}
+
+func (f *FilterByReportingInstance) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "reporting-instance", unmatchedValues(f.Instances, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.ReportingInstance}
+	})
+}
+
+// FilterByFieldPath matches events by the field path of the involved object, which identifies a container
+// (e.g. spec.containers{istio-proxy}).
+type FilterByFieldPath struct {
+	FieldPaths sets.String
+}
+
+func (f *FilterByFieldPath) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByFieldPath) Accept(event *corev1.Event) bool {
+	return util.AcceptString(f.FieldPaths, event.InvolvedObject.FieldPath)
+}
+
+func (f *FilterByFieldPath) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "field-path", unmatchedValues(f.FieldPaths, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.InvolvedObject.FieldPath}
+	})
+}
+
+// FilterByHosts matches events reported from the given hosts, by either Source.Host or ReportingInstance since
+// different API versions populate only one of them.  An event matches when either field is included and neither is
+// excluded.
+type FilterByHosts struct {
+	Hosts sets.String
+}
+
+func (f *FilterByHosts) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByHosts) Accept(event *corev1.Event) bool {
+	return util.AcceptAnyString(f.Hosts, event.Source.Host, event.ReportingInstance)
+}
+
+func (f *FilterByHosts) UnmatchedValues(events []*corev1.Event) (string, []string) {
+	return "host", unmatchedValues(f.Hosts, events, false, false, func(event *corev1.Event) []string {
+		return []string{event.Source.Host, event.ReportingInstance}
+	})
+}
+
+// eventFieldSelectorFields are the event fields kube-apiserver supports in field selectors.
+var eventFieldSelectorFields = sets.NewString(
+	"metadata.name",
+	"metadata.namespace",
+	"involvedObject.kind",
+	"involvedObject.namespace",
+	"involvedObject.name",
+	"involvedObject.uid",
+	"involvedObject.apiVersion",
+	"involvedObject.resourceVersion",
+	"involvedObject.fieldPath",
+	"reason",
+	"reportingComponent",
+	"source",
+	"type",
+)
+
+// FilterByFieldSelector matches events with a field selector, using the same fields as
+// `kubectl get events --field-selector`.
+type FilterByFieldSelector struct {
+	Selector fields.Selector
+}
+
+func NewFilterByFieldSelector(selector string) (*FilterByFieldSelector, error) {
+	parsed, err := fields.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, requirement := range parsed.Requirements() {
+		if !eventFieldSelectorFields.Has(requirement.Field) {
+			return nil, fmt.Errorf("%q is not a supported event field selector, must be one of %s", requirement.Field, strings.Join(eventFieldSelectorFields.List(), ", "))
+		}
+	}
+
+	return &FilterByFieldSelector{Selector: parsed}, nil
+}
+
+func (f *FilterByFieldSelector) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByFieldSelector) Accept(event *corev1.Event) bool {
+	return f.Selector.Matches(eventFields(event))
+}
+
+// eventFields mirrors the selectable fields kube-apiserver computes for events.
+func eventFields(event *corev1.Event) fields.Set {
+	return fields.Set{
+		"metadata.name":                  event.Name,
+		"metadata.namespace":             event.Namespace,
+		"involvedObject.kind":            event.InvolvedObject.Kind,
+		"involvedObject.namespace":       event.InvolvedObject.Namespace,
+		"involvedObject.name":            event.InvolvedObject.Name,
+		"involvedObject.uid":             string(event.InvolvedObject.UID),
+		"involvedObject.apiVersion":      event.InvolvedObject.APIVersion,
+		"involvedObject.resourceVersion": event.InvolvedObject.ResourceVersion,
+		"involvedObject.fieldPath":       event.InvolvedObject.FieldPath,
+		"reason":                         event.Reason,
+		"reportingComponent":             event.ReportingController,
+		"source":                         event.Source.Component,
+		"type":                           event.Type,
+	}
+}
+
+// InvolvedObjectLookup fetches an involved object, for filters that need more than the event records about it.
+type InvolvedObjectLookup func(ref corev1.ObjectReference) (metav1.Object, error)
+
+// FilterByInvolvedObjectLabels matches events whose involved object has labels matching Selector.  Events do not carry
+// those labels, so they are read from Labels, keyed by involved object UID, and when Lookup is set the objects missing
+// from Labels are fetched and added to it.  Every distinct missing object costs a lookup, so leave Lookup nil to work
+// offline; events whose object can't be found don't match.  Objects without a UID are always passed to Lookup.  An
+// object Lookup does not find is recorded in Labels with a nil set, so it is only looked up once, while any other
+// error of Lookup is returned by FilterEventsCtx.
+type FilterByInvolvedObjectLabels struct {
+	Selector labels.Selector
+	Labels   map[types.UID]labels.Set
+	Lookup   InvolvedObjectLookup
+}
+
+func (f *FilterByInvolvedObjectLabels) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	ret, _ := f.FilterEventsCtx(context.Background(), events...)
+	return ret
+}
+
+func (f *FilterByInvolvedObjectLabels) FilterEventsCtx(ctx context.Context, events ...*corev1.Event) ([]*corev1.Event, error) {
+	if f.Labels == nil {
+		f.Labels = map[types.UID]labels.Set{}
+	}
+	return filterByInvolvedObjectMetadata(ctx, events, f.Selector, f.Labels, f.Lookup, metav1.Object.GetLabels)
+}
+
+// FilterByInvolvedObjectAnnotations matches events whose involved object has annotations matching Selector, read the
+// same way FilterByInvolvedObjectLabels reads labels, objects without a UID included.
+type FilterByInvolvedObjectAnnotations struct {
+	Selector    labels.Selector
+	Annotations map[types.UID]labels.Set
+	Lookup      InvolvedObjectLookup
+}
+
+func (f *FilterByInvolvedObjectAnnotations) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	ret, _ := f.FilterEventsCtx(context.Background(), events...)
+	return ret
+}
+
+func (f *FilterByInvolvedObjectAnnotations) FilterEventsCtx(ctx context.Context, events ...*corev1.Event) ([]*corev1.Event, error) {
+	if f.Annotations == nil {
+		f.Annotations = map[types.UID]labels.Set{}
+	}
+	return filterByInvolvedObjectMetadata(ctx, events, f.Selector, f.Annotations, f.Lookup, metav1.Object.GetAnnotations)
+}
+
+func filterByInvolvedObjectMetadata(ctx context.Context, events []*corev1.Event, selector labels.Selector, known map[types.UID]labels.Set, lookup InvolvedObjectLookup, metadata func(metav1.Object) map[string]string) ([]*corev1.Event, error) {
+	ret := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		event := events[i]
+
+		// objects without a UID can't be told apart by it, so they are never read from or added to known and the
+		// lookup, like the one of NewInvolvedObjectLookup, has to cache them itself
+		uid := event.InvolvedObject.UID
+		set, ok := labels.Set(nil), false
+		if len(uid) > 0 {
+			set, ok = known[uid]
+		}
+		if !ok && lookup != nil {
+			// every lookup may be slow, so check for cancellation before each
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			obj, err := lookup(event.InvolvedObject)
+			switch {
+			case errors.IsNotFound(err):
+				// a nil set records the miss, found objects without any labels or annotations have an empty one
+				set = nil
+			case err != nil:
+				ref := event.InvolvedObject
+				return nil, fmt.Errorf("error looking up %s %s/%s: %v", ref.Kind, ref.Namespace, ref.Name, err)
+			default:
+				set = labels.Set(metadata(obj))
+				if set == nil {
+					set = labels.Set{}
+				}
+			}
+			if len(uid) > 0 {
+				known[uid] = set
+			}
+			ok = true
+		}
+		if !ok || set == nil {
+			continue
+		}
+
+		if selector.Matches(set) {
+			ret = append(ret, event)
+		}
+	}
+
+	return ret, nil
+}
+
+// FilterByAPIVersion matches events by the API version of the involved object, comparing group and version separately
+// so "*" matches any group or version, e.g. apps/*, */v1 or v1 for the core group.  It only looks at the version, when
+// chained with FilterByKind an event has to match both, so apps/v1beta1 and Deployment.apps select only the
+// deployments reported through v1beta1.
+type FilterByAPIVersion struct {
+	APIVersions map[schema.GroupVersion]bool
+}
+
+// ParseAPIVersions parses API versions as group/version, or version for the core group, where "*" stands for any group
+// or version.  A lone "*" matches every API version.
+func ParseAPIVersions(apiVersions []string) (map[schema.GroupVersion]bool, error) {
+	ret := map[schema.GroupVersion]bool{}
+	for _, apiVersion := range apiVersions {
+		if apiVersion == "*" {
+			ret[schema.GroupVersion{Group: "*", Version: "*"}] = true
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, err
+		}
+		ret[gv] = true
+	}
+	return ret, nil
+}
+
+func (f *FilterByAPIVersion) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByAPIVersion) Accept(event *corev1.Event) bool {
+	gv, err := schema.ParseGroupVersion(event.InvolvedObject.APIVersion)
+	if err != nil {
+		return false
+	}
+	for curr := range f.APIVersions {
+		if (curr.Group == "*" || curr.Group == gv.Group) && (curr.Version == "*" || curr.Version == gv.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByKind matches events by the group and kind of the involved object.  A kind prefixed with "-" excludes it and
+// "*" stands for any group or any kind.  The most specific entry decides: an exact group and kind, then the kind in any
+// group, then any kind in the group, then any kind in any group.  At the same level an exclusion wins, and when nothing
+// decides the event matches only if every entry is an exclusion.
+type FilterByKind struct {
+	Kinds map[schema.GroupKind]bool
+}
+
+// ParseKindFilter parses the kinds for FilterByKind, resolving resource names with the kinds built into Kubernetes, see
+// ParseKindFilterWithMapper.
+func ParseKindFilter(args []string) (map[schema.GroupKind]bool, error) {
+	return ParseKindFilterWithMapper(args, builtinKindMapper())
+}
+
+// ParseKindFilterWithMapper parses the kinds for FilterByKind.  Each value is in the core group, value.group or
+// group/value, where a group/value group may contain dots.  A value is a Kind or a resource, singular or plural, in any
+// case, resolved to the Kind in the involved object of events with the mapper, so Pod, pod and pods are the same and
+// apps/deployments is Deployment.apps.  "*" stands for any kind or any group and a lone "*" matches every kind in
+// every group.  A "-" prefix excludes the kind, as in -pods, -Deployment.apps or -apps/Deployment, and a lone "-*"
+// excludes every kind.  Values the mapper can't resolve are rejected, except Kinds outside the core group, like those
+// of custom resources, which are taken as they are.
+func ParseKindFilterWithMapper(args []string, mapper meta.RESTMapper) (map[schema.GroupKind]bool, error) {
+	ret := map[schema.GroupKind]bool{}
+	for _, arg := range args {
+		exclude := strings.HasPrefix(arg, "-")
+		value := strings.TrimPrefix(arg, "-")
+
+		gk := schema.GroupKind{}
+		switch {
+		case value == "*":
+			gk = schema.GroupKind{Group: "*", Kind: "*"}
+		case strings.Contains(value, "/"):
+			parts := strings.Split(value, "/")
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid kind %q: expected Kind, Kind.group or group/Kind", arg)
+			}
+			gk = schema.GroupKind{Group: parts[0], Kind: parts[1]}
+			if len(gk.Group) == 0 {
+				return nil, fmt.Errorf("invalid kind %q: missing group before /", arg)
+			}
+		default:
+			parts := strings.SplitN(value, ".", 2)
+			gk.Kind = parts[0]
+			if len(parts) == 2 {
+				gk.Group = parts[1]
+				if len(gk.Group) == 0 {
+					return nil, fmt.Errorf("invalid kind %q: missing group after .", arg)
+				}
+			}
+		}
+		if len(gk.Kind) == 0 {
+			return nil, fmt.Errorf("invalid kind %q: missing kind", arg)
+		}
+
+		kinds, err := resolveKinds(mapper, gk)
+		if err != nil {
+			return nil, fmt.Errorf("invalid kind %q: %v", arg, err)
+		}
+		for _, kind := range kinds {
+			if exclude {
+				kind.Kind = "-" + kind.Kind
+			}
+			ret[kind] = true
+		}
+	}
+	return ret, nil
+}
+
+// resolveKinds returns the kinds the Kind of gk names as a Kind or resource in its group, where "*" stands for any.
+func resolveKinds(mapper meta.RESTMapper, gk schema.GroupKind) ([]schema.GroupKind, error) {
+	if gk.Kind == "*" {
+		return []schema.GroupKind{gk}, nil
+	}
+
+	// without a group the mapper looks in every group, the core group is picked out below
+	gvr := schema.GroupVersionResource{Resource: strings.ToLower(gk.Kind)}
+	if gk.Group != "*" {
+		gvr.Group = gk.Group
+	}
+	gvks, err := mapper.KindsFor(gvr)
+	if err != nil && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+
+	ret := []schema.GroupKind{}
+	seen := map[schema.GroupKind]bool{}
+	others := sets.NewString()
+	for _, gvk := range gvks {
+		resolved := gvk.GroupKind()
+		switch {
+		case gk.Group == "*":
+			resolved.Group = "*"
+		case len(gk.Group) == 0 && len(gvk.Group) > 0:
+			others.Insert(gvk.Kind + "." + gvk.Group)
+			continue
+		}
+		if !seen[resolved] {
+			seen[resolved] = true
+			ret = append(ret, resolved)
+		}
+	}
+	if len(ret) > 0 {
+		return ret, nil
+	}
+
+	switch {
+	case others.Len() > 0:
+		return nil, fmt.Errorf("%s is not in the core group, did you mean %s?", gk.Kind, strings.Join(others.List(), " or "))
+	case len(gk.Group) == 0:
+		return nil, fmt.Errorf("unknown kind or resource %s in the core group", gk.Kind)
+	case !unicode.IsUpper([]rune(gk.Kind)[0]):
+		return nil, fmt.Errorf("unknown resource %s, give the Kind of custom resources, e.g. ClusterOperator.config.openshift.io", gk.Kind)
+	}
+	return []schema.GroupKind{gk}, nil
+}
+
+var (
+	builtinKindMapperOnce sync.Once
+	builtinKinds          meta.RESTMapper
+)
+
+// builtinKindMapper returns a RESTMapper of the kinds built into Kubernetes, which needs no cluster.
+func builtinKindMapper() meta.RESTMapper {
+	builtinKindMapperOnce.Do(func() {
+		mapper := meta.NewDefaultRESTMapper(scheme.Scheme.PrioritizedVersionsAllGroups())
+		for gvk := range scheme.Scheme.AllKnownTypes() {
+			if gvk.Version == runtime.APIVersionInternal || strings.HasSuffix(gvk.Kind, "List") {
+				continue
+			}
+			mapper.Add(gvk, meta.RESTScopeNamespace)
+		}
+		builtinKinds = mapper
+	})
+	return builtinKinds
+}
+
+func (f *FilterByKind) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return filterByPredicate(f, events)
+}
+
+func (f *FilterByKind) Accept(event *corev1.Event) bool {
+	gv, _ := schema.ParseGroupVersion(event.InvolvedObject.APIVersion)
+	return f.matches(gv.WithKind(event.InvolvedObject.Kind).GroupKind())
+}
+
+func (f *FilterByKind) matches(gk schema.GroupKind) bool {
+	levels := []schema.GroupKind{
+		gk,
+		{Group: "*", Kind: gk.Kind},
+		{Group: gk.Group, Kind: "*"},
+		{Group: "*", Kind: "*"},
+	}
+	for _, level := range levels {
+		if f.Kinds[schema.GroupKind{Group: level.Group, Kind: "-" + level.Kind}] {
+			return false
+		}
+		if f.Kinds[level] {
+			return true
+		}
+	}
+
+	// if all values are negation, assume * by default
+	for kind := range f.Kinds {
+		if !strings.HasPrefix(kind.Kind, "-") {
+			return false
+		}
+	}
+	return true
+}
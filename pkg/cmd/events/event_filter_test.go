@@ -0,0 +1,1067 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// testEvent returns a Warning event about the pod name in the namespace, observed once at t.
+func testEvent(namespace, name, reason string, t time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name + "." + reason},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Namespace:  namespace,
+			Name:       name,
+			UID:        types.UID(namespace + "-" + name),
+		},
+		Reason:         reason,
+		Message:        reason + " " + name,
+		Type:           corev1.EventTypeWarning,
+		Count:          1,
+		FirstTimestamp: metav1.NewTime(t),
+		LastTimestamp:  metav1.NewTime(t),
+	}
+}
+
+// testLookup returns a lookup of the objects by name, counting the lookups of each.
+func testLookup(objects map[string]*metav1.ObjectMeta, lookups map[string]int) InvolvedObjectLookup {
+	return func(ref corev1.ObjectReference) (metav1.Object, error) {
+		lookups[ref.Name]++
+		obj, ok := objects[ref.Name]
+		if !ok {
+			return nil, errors.NewNotFound(schema.GroupResource{Resource: "pods"}, ref.Name)
+		}
+		return obj, nil
+	}
+}
+
+// eventNames returns the names of the events, in order.
+func eventNames(events []*corev1.Event) []string {
+	ret := []string{}
+	for _, event := range events {
+		ret = append(ret, event.Name)
+	}
+	return ret
+}
+
+func TestFilterByInvolvedObjectLabelsWithoutUID(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	a, b := testEvent("ns", "a", "BackOff", base), testEvent("ns", "b", "BackOff", base)
+	a.InvolvedObject.UID, b.InvolvedObject.UID = "", ""
+
+	objects := map[string]*metav1.ObjectMeta{
+		"a": {Name: "a", Labels: map[string]string{"app": "a"}},
+		"b": {Name: "b", Labels: map[string]string{"app": "b"}},
+	}
+	lookups := map[string]int{}
+	filter := &FilterByInvolvedObjectLabels{Selector: labels.SelectorFromSet(labels.Set{"app": "a"}), Lookup: testLookup(objects, lookups)}
+
+	got := eventNames(filter.FilterEvents(a, b))
+	if len(got) != 1 || got[0] != a.Name {
+		t.Errorf("expected only %s to match, got %v", a.Name, got)
+	}
+	if lookups["a"] != 1 || lookups["b"] != 1 {
+		t.Errorf("expected each object to be looked up once, got %v", lookups)
+	}
+	if len(filter.Labels) != 0 {
+		t.Errorf("expected objects without a UID not to be cached, got %v", filter.Labels)
+	}
+}
+
+func TestFilterByInvolvedObjectAnnotationsWithoutUID(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	a, b := testEvent("ns", "a", "BackOff", base), testEvent("ns", "b", "BackOff", base)
+	a.InvolvedObject.UID, b.InvolvedObject.UID = "", ""
+
+	objects := map[string]*metav1.ObjectMeta{
+		"a": {Name: "a", Annotations: map[string]string{"team": "a"}},
+		"b": {Name: "b", Annotations: map[string]string{"team": "b"}},
+	}
+	selector, err := labels.Parse("team=b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lookups := map[string]int{}
+	filter := &FilterByInvolvedObjectAnnotations{Selector: selector, Lookup: testLookup(objects, lookups)}
+
+	// in both orders, so neither object can decide for the other
+	for _, events := range [][]*corev1.Event{{a, b}, {b, a}} {
+		got := eventNames(filter.FilterEvents(events...))
+		if len(got) != 1 || got[0] != b.Name {
+			t.Errorf("expected only %s to match, got %v", b.Name, got)
+		}
+	}
+	if lookups["a"] != 2 || lookups["b"] != 2 {
+		t.Errorf("expected each object to be looked up on every pass, got %v", lookups)
+	}
+}
+
+func TestFilterByInvolvedObjectLabelsLookupErrors(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	found, gone := testEvent("ns", "found", "BackOff", base), testEvent("ns", "gone", "BackOff", base)
+	bare := testEvent("ns", "bare", "BackOff", base)
+
+	objects := map[string]*metav1.ObjectMeta{
+		"found": {Name: "found", Labels: map[string]string{"app": "web"}},
+		"bare":  {Name: "bare"},
+	}
+	lookups := map[string]int{}
+	filter := &FilterByInvolvedObjectLabels{Selector: labels.Everything(), Lookup: testLookup(objects, lookups)}
+
+	for i := 0; i < 2; i++ {
+		got, err := filter.FilterEventsCtx(context.Background(), found, gone, bare)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names := eventNames(got); !reflect.DeepEqual(names, []string{found.Name, bare.Name}) {
+			t.Errorf("expected the objects that were found to match, got %v", names)
+		}
+	}
+	if lookups["found"] != 1 || lookups["gone"] != 1 || lookups["bare"] != 1 {
+		t.Errorf("expected every object to be looked up once, found or not, got %v", lookups)
+	}
+
+	forbidden := &FilterByInvolvedObjectLabels{
+		Selector: labels.Everything(),
+		Lookup: func(ref corev1.ObjectReference) (metav1.Object, error) {
+			return nil, errors.NewForbidden(schema.GroupResource{Resource: "pods"}, ref.Name, fmt.Errorf("denied"))
+		},
+	}
+	if _, err := forbidden.FilterEventsCtx(context.Background(), found); err == nil {
+		t.Errorf("expected the forbidden lookup to be returned")
+	}
+	if _, err := (EventFilters{&NotFilter{Delegate: forbidden}}).FilterEventsCtx(context.Background(), found); err == nil {
+		t.Errorf("expected the forbidden lookup not to be inverted into a match")
+	}
+	if len(forbidden.Labels) != 0 {
+		t.Errorf("expected a failed lookup not to be cached, got %v", forbidden.Labels)
+	}
+}
+
+func TestFilterByClusterScoped(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := testEvent("ns", "a", "BackOff", base)
+	other := testEvent("other", "b", "BackOff", base)
+	node := testEvent("", "master-0", "NodeNotReady", base)
+	node.InvolvedObject.Kind = "Node"
+	// events about nodes are recorded in the default namespace, the involved object tells they are cluster-scoped
+	node.Namespace = "default"
+	volume := testEvent("", "pv-1", "VolumeFailedRecycle", base)
+	volume.InvolvedObject.Kind = "PersistentVolume"
+	events := []*corev1.Event{pod, node, other, volume}
+
+	tests := []struct {
+		name     string
+		filters  EventFilters
+		expected []*corev1.Event
+	}{
+		{name: "cluster-scoped", filters: EventFilters{&FilterByClusterScoped{}}, expected: []*corev1.Event{node, volume}},
+		{name: "namespaced", filters: EventFilters{&FilterByClusterScoped{Namespaced: true}}, expected: []*corev1.Event{pod, other}},
+		{name: "both", filters: EventFilters{&FilterByClusterScoped{}, &FilterByClusterScoped{Namespaced: true}}, expected: []*corev1.Event{}},
+		{name: "namespaced in a namespace", filters: EventFilters{&FilterByClusterScoped{Namespaced: true}, &FilterByNamespaces{Namespaces: sets.NewString("ns")}}, expected: []*corev1.Event{pod}},
+		{name: "cluster-scoped in a namespace", filters: EventFilters{&FilterByClusterScoped{}, &FilterByNamespaces{Namespaces: sets.NewString("ns")}}, expected: []*corev1.Event{}},
+		{name: "cluster-scoped outside a namespace", filters: EventFilters{&FilterByClusterScoped{}, &FilterByNamespaces{Namespaces: sets.NewString("-ns")}}, expected: []*corev1.Event{node, volume}},
+		{name: "cluster-scoped in the empty namespace", filters: EventFilters{&FilterByNamespaces{Namespaces: sets.NewString("")}, &FilterByClusterScoped{}}, expected: []*corev1.Event{node, volume}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.filters.FilterEvents(events...); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", eventNames(test.expected), eventNames(got))
+			}
+		})
+	}
+}
+
+func TestFilterByBenign(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	pulled := testEvent("ns", "a", "Pulled", base)
+	pulled.Type = corev1.EventTypeNormal
+	killing := testEvent("ns", "a", "Killing", base)
+	killing.Type = corev1.EventTypeNormal
+	warning := testEvent("ns", "a", "Pulled", base)
+
+	tests := []struct {
+		name   string
+		filter *FilterByBenign
+		want   []*corev1.Event
+	}{
+		{name: "default reasons", filter: NewFilterByBenign(nil), want: []*corev1.Event{killing, warning}},
+		{name: "given reasons", filter: NewFilterByBenign([]string{"Killing"}), want: []*corev1.Event{pulled, warning}},
+		{name: "no reasons", filter: &FilterByBenign{}, want: []*corev1.Event{pulled, killing, warning}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.filter.FilterEvents(pulled, killing, warning)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", eventNames(test.want), eventNames(got))
+			}
+		})
+	}
+
+	if len(DefaultBenignReasons) != NewFilterByBenign(nil).Reasons.Len() {
+		t.Errorf("expected the default reasons to be used")
+	}
+}
+
+func TestFilterByReasonsFollowsItsFields(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := testEvent("ns", "a", "FailedMount", base)
+
+	filter := &FilterByReasons{Reasons: sets.NewString("failed"), IgnoreCase: true, Prefix: true}
+	if !filter.Accept(event) {
+		t.Errorf("expected failed to match FailedMount ignoring case as a prefix")
+	}
+	filter.Reasons = sets.NewString("backoff")
+	if filter.Accept(event) {
+		t.Errorf("expected the changed reasons to be used")
+	}
+	if filter.acceptedReasons != nil {
+		t.Errorf("expected the filter not to be modified when filtering")
+	}
+
+	built := NewFilterByReasons([]string{"Failed"}, false, true)
+	if !built.Accept(event) || built.Accept(testEvent("ns", "a", "BackOff", base)) {
+		t.Errorf("expected Failed to match only the reasons it is a prefix of")
+	}
+}
+
+func TestFilterByReasonsPrefix(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	failed := testEvent("ns", "a", "Failed", base)
+	mount := testEvent("ns", "b", "FailedMount", base)
+	scheduling := testEvent("ns", "c", "FailedScheduling", base)
+	back := testEvent("ns", "d", "Back", base)
+	backOff := testEvent("ns", "e", "BackOff", base)
+	lowerBackOff := testEvent("ns", "f", "backoff", base)
+	events := []*corev1.Event{failed, mount, scheduling, back, backOff, lowerBackOff}
+
+	tests := []struct {
+		name       string
+		reasons    []string
+		ignoreCase bool
+		want       []*corev1.Event
+	}{
+		{name: "prefix", reasons: []string{"Failed"}, want: []*corev1.Event{failed, mount, scheduling}},
+		{name: "prefix with an exclusion", reasons: []string{"Failed", "-FailedMount"}, want: []*corev1.Event{failed, scheduling}},
+		{name: "exclusions only", reasons: []string{"-FailedMount", "-Back"}, want: []*corev1.Event{failed, scheduling, lowerBackOff}},
+		{name: "shorter nested prefix", reasons: []string{"Back"}, want: []*corev1.Event{back, backOff}},
+		{name: "longer nested prefix", reasons: []string{"BackOff"}, want: []*corev1.Event{backOff}},
+		{name: "nested prefixes together", reasons: []string{"Back", "BackOff"}, want: []*corev1.Event{back, backOff}},
+		{name: "shorter prefix without the longer one", reasons: []string{"Back", "-BackOff"}, want: []*corev1.Event{back}},
+		{name: "prefix ignoring case", reasons: []string{"back"}, ignoreCase: true, want: []*corev1.Event{back, backOff, lowerBackOff}},
+		{name: "exclusion ignoring case", reasons: []string{"FAILED", "-failedmount"}, ignoreCase: true, want: []*corev1.Event{failed, scheduling}},
+		{name: "explicit star", reasons: []string{"Failed*"}, want: []*corev1.Event{failed, mount, scheduling}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := NewFilterByReasons(test.reasons, test.ignoreCase, true).FilterEvents(events...)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", eventNames(test.want), eventNames(got))
+			}
+		})
+	}
+}
+
+func TestParseKindFilter(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    map[schema.GroupKind]bool
+		wantErr bool
+	}{
+		{args: []string{"Pod"}, want: map[schema.GroupKind]bool{{Kind: "Pod"}: true}},
+		{args: []string{"pods"}, want: map[schema.GroupKind]bool{{Kind: "Pod"}: true}},
+		{args: []string{"pod"}, want: map[schema.GroupKind]bool{{Kind: "Pod"}: true}},
+		{args: []string{"-pods"}, want: map[schema.GroupKind]bool{{Kind: "-Pod"}: true}},
+		{args: []string{"apps/deployments"}, want: map[schema.GroupKind]bool{{Group: "apps", Kind: "Deployment"}: true}},
+		{args: []string{"deployments.apps"}, want: map[schema.GroupKind]bool{{Group: "apps", Kind: "Deployment"}: true}},
+		{args: []string{"-Deployment.apps"}, want: map[schema.GroupKind]bool{{Group: "apps", Kind: "-Deployment"}: true}},
+		{args: []string{"storageclasses.storage"}, want: map[schema.GroupKind]bool{{Group: "storage.k8s.io", Kind: "StorageClass"}: true}},
+		{args: []string{"*/pods"}, want: map[schema.GroupKind]bool{{Group: "*", Kind: "Pod"}: true}},
+		{args: []string{"apps/*"}, want: map[schema.GroupKind]bool{{Group: "apps", Kind: "*"}: true}},
+		{args: []string{"*"}, want: map[schema.GroupKind]bool{{Group: "*", Kind: "*"}: true}},
+		{args: []string{"*/*"}, want: map[schema.GroupKind]bool{{Group: "*", Kind: "*"}: true}},
+		{args: []string{"-*"}, want: map[schema.GroupKind]bool{{Group: "*", Kind: "-*"}: true}},
+		{args: []string{"ClusterOperator.config.openshift.io"}, want: map[schema.GroupKind]bool{{Group: "config.openshift.io", Kind: "ClusterOperator"}: true}},
+		{args: []string{"config.openshift.io/ClusterOperator"}, want: map[schema.GroupKind]bool{{Group: "config.openshift.io", Kind: "ClusterOperator"}: true}},
+
+		{args: []string{"deployments"}, wantErr: true},
+		{args: []string{"pdos"}, wantErr: true},
+		{args: []string{"clusteroperators.config.openshift.io"}, wantErr: true},
+		{args: []string{"a/b/c"}, wantErr: true},
+		{args: []string{"/pods"}, wantErr: true},
+		{args: []string{"pods."}, wantErr: true},
+		{args: []string{"apps/"}, wantErr: true},
+		{args: []string{"-"}, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseKindFilter(test.args)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%v: expected an error, got %v", test.args, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", test.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: expected %v, got %v", test.args, test.want, got)
+		}
+	}
+}
+
+func TestParseKindFilterMatchesEvents(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := testEvent("ns", "a", "BackOff", base)
+	deployment := testEvent("ns", "web", "ScalingReplicaSet", base)
+	deployment.InvolvedObject.APIVersion, deployment.InvolvedObject.Kind = "apps/v1", "Deployment"
+
+	for _, test := range []struct {
+		kinds []string
+		want  []*corev1.Event
+	}{
+		{kinds: []string{"pods"}, want: []*corev1.Event{pod}},
+		{kinds: []string{"apps/deployments"}, want: []*corev1.Event{deployment}},
+		{kinds: []string{"-pods"}, want: []*corev1.Event{deployment}},
+	} {
+		kinds, err := ParseKindFilter(test.kinds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := (&FilterByKind{Kinds: kinds}).FilterEvents(pod, deployment)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v: expected %v, got %v", test.kinds, eventNames(test.want), eventNames(got))
+		}
+	}
+}
+
+func TestAnyFilterInsideEventFilters(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	scheduling := testEvent("foo", "a", "FailedScheduling", base)
+	backOff := testEvent("foo", "b", "BackOff", base)
+	mount := testEvent("foo", "c", "FailedMount", base)
+	otherBackOff := testEvent("bar", "d", "BackOff", base)
+	events := []*corev1.Event{scheduling, backOff, mount, otherBackOff}
+
+	reason := func(reason string) EventFilter { return &FilterByReasons{Reasons: sets.NewString(reason)} }
+	namespace := func(namespace string) EventFilter {
+		return &FilterByNamespaces{Namespaces: sets.NewString(namespace)}
+	}
+
+	tests := []struct {
+		name    string
+		filters EventFilters
+		want    []*corev1.Event
+	}{
+		{
+			name:    "(FailedScheduling or BackOff) and foo",
+			filters: EventFilters{&AnyFilter{Filters: []EventFilter{reason("FailedScheduling"), reason("BackOff")}}, namespace("foo")},
+			want:    []*corev1.Event{scheduling, backOff},
+		},
+		{
+			name:    "foo and (FailedScheduling or BackOff)",
+			filters: EventFilters{namespace("foo"), &AnyFilter{Filters: []EventFilter{reason("FailedScheduling"), reason("BackOff")}}},
+			want:    []*corev1.Event{scheduling, backOff},
+		},
+		{
+			name: "(FailedMount or bar) and (BackOff or FailedMount)",
+			filters: EventFilters{
+				&AnyFilter{Filters: []EventFilter{reason("FailedMount"), namespace("bar")}},
+				&AnyFilter{Filters: []EventFilter{reason("BackOff"), reason("FailedMount")}},
+			},
+			// in the order the alternatives of the last filter matched them
+			want: []*corev1.Event{otherBackOff, mount},
+		},
+		{
+			name:    "overlapping alternatives return each event once",
+			filters: EventFilters{&AnyFilter{Filters: []EventFilter{reason("BackOff"), namespace("foo")}}},
+			want:    []*corev1.Event{backOff, otherBackOff, scheduling, mount},
+		},
+		{
+			name:    "no alternatives match nothing",
+			filters: EventFilters{&AnyFilter{}},
+			want:    []*corev1.Event{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.filters.FilterEvents(events...)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", eventNames(test.want), eventNames(got))
+			}
+		})
+	}
+}
+
+func TestAnyFilterDeduplicatesCopies(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, second := testEvent("ns", "a", "BackOff", base), testEvent("ns", "a", "BackOff", base.Add(time.Minute))
+	first.UID, second.UID = "event-uid", "event-uid"
+
+	// both alternatives return their own copy of the same collapsed event
+	filter := &AnyFilter{Filters: []EventFilter{&FilterByDedup{}, &FilterByDedup{}}}
+	if got := filter.FilterEvents(first, second); len(got) != 1 || got[0].Count != 2 {
+		t.Errorf("expected one collapsed event, got %d", len(got))
+	}
+}
+
+func TestDedupEvents(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	// copyOf returns a copy of event named name, as read from another source, with the count and last timestamp changed
+	copyOf := func(event *corev1.Event, name string, count int32, last time.Time) *corev1.Event {
+		ret := event.DeepCopy()
+		ret.Name, ret.Count, ret.LastTimestamp = name, count, metav1.NewTime(last)
+		return ret
+	}
+	backOff := testEvent("ns", "a", "BackOff", base)
+	pulled := testEvent("ns", "b", "Pulled", base)
+	otherPod := testEvent("ns", "c", "BackOff", base)
+
+	tests := []struct {
+		name     string
+		events   []*corev1.Event
+		expected []string
+	}{
+		{
+			name:     "highest count wins",
+			events:   []*corev1.Event{copyOf(backOff, "low", 2, base), copyOf(backOff, "high", 5, base), copyOf(backOff, "mid", 3, base.Add(time.Hour))},
+			expected: []string{"high"},
+		},
+		{
+			name:     "latest last timestamp breaks a tie",
+			events:   []*corev1.Event{copyOf(backOff, "early", 3, base), copyOf(backOff, "late", 3, base.Add(time.Minute)), copyOf(backOff, "between", 3, base.Add(time.Second))},
+			expected: []string{"late"},
+		},
+		{
+			name:     "first copy wins a full tie",
+			events:   []*corev1.Event{copyOf(backOff, "first", 3, base), copyOf(backOff, "second", 3, base)},
+			expected: []string{"first"},
+		},
+		{
+			name: "survivors keep the position of their first copy",
+			events: []*corev1.Event{
+				copyOf(pulled, "pulled", 1, base),
+				copyOf(backOff, "backoff", 1, base),
+				copyOf(otherPod, "other", 1, base),
+				copyOf(backOff, "backoff again", 4, base),
+				copyOf(pulled, "pulled again", 2, base),
+			},
+			expected: []string{"pulled again", "backoff again", "other"},
+		},
+		{
+			name:     "copies differing only by timestamp collapse",
+			events:   []*corev1.Event{copyOf(backOff, "older", 1, base), copyOf(backOff, "newer", 1, base.Add(10*time.Minute))},
+			expected: []string{"newer"},
+		},
+		{
+			name:     "events about different objects are kept",
+			events:   []*corev1.Event{copyOf(backOff, "a", 1, base), copyOf(otherPod, "c", 1, base)},
+			expected: []string{"a", "c"},
+		},
+		{
+			name:     "no events",
+			events:   nil,
+			expected: []string{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := eventNames(DedupEvents(test.events)); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterByComponent(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	kubelet := testEvent("ns", "a", "BackOff", base)
+	kubelet.Source.Component = "kubelet"
+	scheduler := testEvent("ns", "b", "FailedScheduling", base)
+	scheduler.ReportingController = "default-scheduler"
+	both := testEvent("ns", "c", "Unhealthy", base)
+	both.ReportingController, both.Source.Component = "kubelet", "kubelet"
+	neither := testEvent("ns", "d", "Custom", base)
+	events := []*corev1.Event{kubelet, scheduler, both, neither}
+
+	tests := []struct {
+		components []string
+		expected   []*corev1.Event
+	}{
+		{components: []string{"kubelet"}, expected: []*corev1.Event{kubelet, both}},
+		{components: []string{"default-scheduler"}, expected: []*corev1.Event{scheduler}},
+		{components: []string{"kubelet", "default-scheduler"}, expected: []*corev1.Event{kubelet, scheduler, both}},
+		{components: []string{"*-scheduler"}, expected: []*corev1.Event{scheduler}},
+		{components: []string{"-kubelet"}, expected: []*corev1.Event{scheduler, neither}},
+		{components: []string{"-default-scheduler"}, expected: []*corev1.Event{kubelet, both, neither}},
+		{components: []string{"*", "-kubelet"}, expected: []*corev1.Event{scheduler, neither}},
+		{components: []string{"etcd"}, expected: []*corev1.Event{}},
+	}
+	for _, test := range tests {
+		got := (&FilterByComponent{Components: sets.NewString(test.components...)}).FilterEvents(events...)
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%v: expected %v, got %v", test.components, eventNames(test.expected), eventNames(got))
+		}
+	}
+}
+
+func TestFilterChainsOnEmptyInput(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	normal := testEvent("ns", "a", "Scheduled", base)
+	normal.Type = corev1.EventTypeNormal
+
+	around, err := NewFilterByAround("12:00", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeRange, err := NewFilterByTimeRange("11:00", "13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		filters EventFilters
+		events  []*corev1.Event
+	}{
+		{name: "warnings then around", filters: EventFilters{&FilterByWarnings{}, around}, events: []*corev1.Event{normal}},
+		{name: "namespace then around", filters: EventFilters{&FilterByNamespaces{Namespaces: sets.NewString("other")}, around}, events: []*corev1.Event{normal}},
+		{name: "warnings then time range", filters: EventFilters{&FilterByWarnings{}, timeRange}, events: []*corev1.Event{normal}},
+		{name: "around alone", filters: EventFilters{around}, events: []*corev1.Event{}},
+		{name: "time range alone", filters: EventFilters{timeRange}, events: []*corev1.Event{}},
+		{name: "around as a filter of its own", filters: EventFilters{&AnyFilter{Filters: []EventFilter{around}}}, events: []*corev1.Event{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.filters.FilterEvents(test.events...)
+			if got == nil || len(got) != 0 {
+				t.Errorf("expected an empty result, got %v", got)
+			}
+		})
+	}
+
+	// filtered directly, without the early return of EventFilters
+	if got := around.FilterEvents(); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty result, got %v", got)
+	}
+	if got := timeRange.FilterEvents(); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty result, got %v", got)
+	}
+}
+
+func TestFilterByKindFilterEvents(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := testEvent("ns", "a", "BackOff", base)
+	deployment := testEvent("ns", "web", "ScalingReplicaSet", base)
+	deployment.InvolvedObject.APIVersion, deployment.InvolvedObject.Kind = "apps/v1", "Deployment"
+	statefulSet := testEvent("ns", "db", "SuccessfulCreate", base)
+	statefulSet.InvolvedObject.APIVersion, statefulSet.InvolvedObject.Kind = "apps/v1", "StatefulSet"
+	operator := testEvent("", "etcd", "OperatorStatusChanged", base)
+	operator.InvolvedObject.APIVersion, operator.InvolvedObject.Kind = "config.openshift.io/v1", "ClusterOperator"
+	events := []*corev1.Event{pod, deployment, statefulSet, operator}
+
+	tests := []struct {
+		name     string
+		kinds    []schema.GroupKind
+		expected []*corev1.Event
+	}{
+		{name: "*/*", kinds: []schema.GroupKind{{Group: "*", Kind: "*"}}, expected: events},
+		{name: "*/Deployment", kinds: []schema.GroupKind{{Group: "*", Kind: "Deployment"}}, expected: []*corev1.Event{deployment}},
+		{name: "apps/*", kinds: []schema.GroupKind{{Group: "apps", Kind: "*"}}, expected: []*corev1.Event{deployment, statefulSet}},
+		{name: "Pod", kinds: []schema.GroupKind{{Kind: "Pod"}}, expected: []*corev1.Event{pod}},
+		{name: "Pod and */*", kinds: []schema.GroupKind{{Kind: "Pod"}, {Group: "*", Kind: "*"}}, expected: events},
+		{
+			name:     "every level matching",
+			kinds:    []schema.GroupKind{{Group: "apps", Kind: "Deployment"}, {Group: "apps", Kind: "*"}, {Group: "*", Kind: "Deployment"}, {Group: "*", Kind: "*"}},
+			expected: events,
+		},
+		{name: "apps/* and -apps/StatefulSet", kinds: []schema.GroupKind{{Group: "apps", Kind: "*"}, {Group: "apps", Kind: "-StatefulSet"}}, expected: []*corev1.Event{deployment}},
+		{name: "*/* and -apps/*", kinds: []schema.GroupKind{{Group: "*", Kind: "*"}, {Group: "apps", Kind: "-*"}}, expected: []*corev1.Event{pod, operator}},
+		{
+			name:     "*/*, -*/Deployment and -Pod",
+			kinds:    []schema.GroupKind{{Group: "*", Kind: "*"}, {Group: "*", Kind: "-Deployment"}, {Kind: "-Pod"}},
+			expected: []*corev1.Event{statefulSet, operator},
+		},
+		{
+			name:     "-apps/* and -ClusterOperator.config.openshift.io",
+			kinds:    []schema.GroupKind{{Group: "apps", Kind: "-*"}, {Group: "config.openshift.io", Kind: "-ClusterOperator"}},
+			expected: []*corev1.Event{pod},
+		},
+		{name: "apps/Deployment and -apps/*", kinds: []schema.GroupKind{{Group: "apps", Kind: "Deployment"}, {Group: "apps", Kind: "-*"}}, expected: []*corev1.Event{deployment}},
+		{name: "Pod and -*", kinds: []schema.GroupKind{{Kind: "Pod"}, {Group: "*", Kind: "-*"}}, expected: []*corev1.Event{pod}},
+		{name: "-*", kinds: []schema.GroupKind{{Group: "*", Kind: "-*"}}, expected: []*corev1.Event{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := &FilterByKind{Kinds: map[schema.GroupKind]bool{}}
+			for _, kind := range test.kinds {
+				filter.Kinds[kind] = true
+			}
+			got := filter.FilterEvents(events...)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", eventNames(test.expected), eventNames(got))
+			}
+		})
+	}
+}
+
+func TestFilterByAroundLocation(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		event    time.Time
+		want     bool
+	}{
+		{name: "EDT", location: newYork, event: time.Date(2020, 7, 1, 14, 0, 0, 0, time.UTC), want: true},
+		{name: "EDT other hour", location: newYork, event: time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC), want: false},
+		{name: "EST", location: newYork, event: time.Date(2020, 1, 15, 15, 0, 0, 0, time.UTC), want: true},
+		{name: "EST is not EDT", location: newYork, event: time.Date(2020, 1, 15, 14, 0, 0, 0, time.UTC), want: false},
+		{name: "fixed zone", location: time.FixedZone("UTC-4", -4*60*60), event: time.Date(2020, 1, 15, 14, 0, 0, 0, time.UTC), want: true},
+		{name: "fixed zone ahead of UTC", location: time.FixedZone("UTC+5:30", 5*60*60+30*60), event: time.Date(2020, 1, 15, 4, 30, 0, 0, time.UTC), want: true},
+		{name: "default UTC", event: time.Date(2020, 7, 1, 10, 0, 0, 0, time.UTC), want: true},
+		{name: "default UTC other hour", event: time.Date(2020, 7, 1, 14, 0, 0, 0, time.UTC), want: false},
+		{name: "default UTC for events in another zone", event: time.Date(2020, 7, 1, 6, 0, 0, 0, newYork), want: true},
+		{name: "default UTC is not the zone of the events", event: time.Date(2020, 7, 1, 10, 0, 0, 0, newYork), want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewFilterByAround("10:00", 30*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			filter.Location = test.location
+			event := testEvent("ns", "a", "BackOff", test.event)
+			if got := len(filter.FilterEvents(event)) == 1; got != test.want {
+				t.Errorf("expected the event at %s to match %v, got %v", test.event, test.want, got)
+			}
+		})
+	}
+}
+
+func TestBuildFiltersAroundTimeZone(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	event := testEvent("ns", "a", "BackOff", time.Date(2020, 7, 1, 14, 0, 0, 0, time.UTC))
+
+	filters, err := BuildFilters(FilterOptions{Around: "10:00", AroundDuration: time.Minute, AroundTimeZone: "America/New_York"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := filters.FilterEvents(event); len(got) != 1 {
+		t.Errorf("expected the event at 14:00 UTC to be around 10:00 EDT, got %v", eventNames(got))
+	}
+
+	if _, err := BuildFilters(FilterOptions{Around: "10:00", AroundTimeZone: "Nowhere/Nothing"}); err == nil {
+		t.Errorf("expected an unknown time zone to be rejected")
+	}
+}
+
+func TestFilterByArounds(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []*corev1.Event{}
+	for _, offset := range []time.Duration{-10, -4, 0, 3, 6, 30, 33, 40} {
+		events = append(events, testEvent("ns", fmt.Sprintf("at%d", offset), "BackOff", base.Add(offset*time.Minute)))
+	}
+	names := func(offsets ...int) []string {
+		ret := []string{}
+		for _, offset := range offsets {
+			ret = append(ret, fmt.Sprintf("at%d.BackOff", offset))
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		arounds  []string
+		expected []string
+	}{
+		{name: "single", arounds: []string{"12:00"}, expected: names(-4, 0, 3)},
+		{name: "disjoint", arounds: []string{"12:00", "12:30"}, expected: names(-4, 0, 3, 30, 33)},
+		{name: "overlapping", arounds: []string{"12:00", "12:03"}, expected: names(-4, 0, 3, 6)},
+		{name: "same twice", arounds: []string{"12:00", "12:00"}, expected: names(-4, 0, 3)},
+		{name: "nested", arounds: []string{"12:00/15m", "12:00"}, expected: names(-10, -4, 0, 3, 6)},
+		{name: "own durations", arounds: []string{"12:00/1m", "12:35/5m"}, expected: names(0, 30, 33, 40)},
+		{name: "own duration with seconds", arounds: []string{"12:03:30/30s"}, expected: names(3)},
+		{name: "nothing around", arounds: []string{"13:00", "14:00/10m"}, expected: names()},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewFilterByArounds(test.arounds, 5*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := eventNames(filter.FilterEvents(events...)); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewFilterByAroundSingle(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	near, far := testEvent("ns", "near", "BackOff", base.Add(4*time.Minute)), testEvent("ns", "far", "BackOff", base.Add(6*time.Minute))
+
+	filter, err := NewFilterByAround("12:00", 5*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filter.Around != "12:00" || len(filter.Arounds) != 0 {
+		t.Errorf("expected only Around to be set, got %q and %v", filter.Around, filter.Arounds)
+	}
+	if got := filter.FilterEvents(near, far); len(got) != 1 || got[0] != near {
+		t.Errorf("expected only %s, got %v", near.Name, eventNames(got))
+	}
+
+	for _, around := range []string{"12:00/5m", "12:00/0s", "12:00/x", "12", "now-x"} {
+		_, err := NewFilterByAround(around, time.Minute)
+		if valid := around == "12:00/5m"; (err == nil) != valid {
+			t.Errorf("%q: expected valid %v, got error %v", around, valid, err)
+		}
+	}
+}
+
+func TestFilterByAroundFollowsItsFields(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	noon, one := testEvent("ns", "noon", "BackOff", base), testEvent("ns", "one", "BackOff", base.Add(time.Hour))
+
+	literal := &FilterByAround{Around: "12:00", AroundDuration: time.Minute}
+	if got := literal.FilterEvents(noon, one); len(got) != 1 || got[0] != noon {
+		t.Errorf("expected a filter built without NewFilterByAround to match %s, got %v", noon.Name, eventNames(got))
+	}
+	literal.Around, literal.Arounds = "", []string{"13:00", "x"}
+	if got := literal.FilterEvents(noon, one); len(got) != 1 || got[0] != one {
+		t.Errorf("expected the changed Arounds to match %s, got %v", one.Name, eventNames(got))
+	}
+
+	built, err := NewFilterByAround("12:00", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	built.Around = "13:00"
+	if got := built.FilterEvents(noon, one); len(got) != 1 || got[0] != one {
+		t.Errorf("expected the changed Around to match %s, got %v", one.Name, eventNames(got))
+	}
+	if len(built.anchors) != 1 || built.parsed[0] != "12:00" {
+		t.Errorf("expected the filter not to be modified when filtering")
+	}
+}
+
+func TestFilterByAroundShuffled(t *testing.T) {
+	day := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []*corev1.Event{
+		testEvent("ns", "yesterday", "BackOff", day.Add(-24*time.Hour+14*time.Hour)),
+		testEvent("ns", "before", "BackOff", day.Add(13*time.Hour+50*time.Minute)),
+		testEvent("ns", "at", "BackOff", day.Add(14*time.Hour)),
+		testEvent("ns", "after", "BackOff", day.Add(14*time.Hour+5*time.Minute)),
+		testEvent("ns", "later", "BackOff", day.Add(16*time.Hour)),
+	}
+	want := sets.NewString("before.BackOff", "at.BackOff", "after.BackOff")
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		shuffled := append([]*corev1.Event{}, events...)
+		random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+		filter, err := NewFilterByAround("14:00", 10*time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := filter.FilterEvents(shuffled...)
+		if names := sets.NewString(eventNames(got)...); !names.Equal(want) {
+			t.Errorf("%v: expected %v, got %v", eventNames(shuffled), want.List(), names.List())
+		}
+		// the events kept keep the order they were passed in
+		for j := 1; j < len(got); j++ {
+			if indexOf(shuffled, got[j-1]) > indexOf(shuffled, got[j]) {
+				t.Errorf("expected the order of %v to be kept, got %v", eventNames(shuffled), eventNames(got))
+			}
+		}
+	}
+}
+
+// indexOf returns the index of the event in events, or -1.
+func indexOf(events []*corev1.Event, event *corev1.Event) int {
+	for i := range events {
+		if events[i] == event {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestStringFilterExclusions(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	kubeSystem := testEvent("kube-system", "etcd", "BackOff", base)
+	kubeSystem.Source.Component, kubeSystem.InvolvedObject.UID = "kubelet", "uid-1"
+	openshift := testEvent("openshift-etcd", "etcd-0", "FailedMount", base)
+	openshift.ReportingController, openshift.InvolvedObject.UID = "kube-controller-manager", "uid-2"
+	user := testEvent("user", "web", "FailedScheduling", base)
+	user.Source.Component, user.InvolvedObject.UID = "default-scheduler", "uid-3"
+	events := []*corev1.Event{kubeSystem, openshift, user}
+
+	filters := map[string]func(values ...string) EventFilter{
+		"namespace": func(values ...string) EventFilter {
+			return &FilterByNamespaces{Namespaces: sets.NewString(values...)}
+		},
+		"name": func(values ...string) EventFilter { return &FilterByNames{Names: sets.NewString(values...)} },
+		"reason": func(values ...string) EventFilter {
+			return NewFilterByReasons(values, false, false)
+		},
+		"component": func(values ...string) EventFilter {
+			return &FilterByComponent{Components: sets.NewString(values...)}
+		},
+		"uid": func(values ...string) EventFilter { return &FilterByUIDs{UIDs: sets.NewString(values...)} },
+	}
+
+	tests := []struct {
+		filter string
+		values []string
+		want   []*corev1.Event
+	}{
+		{filter: "namespace", values: []string{"-kube-system"}, want: []*corev1.Event{openshift, user}},
+		{filter: "namespace", values: []string{"-kube-system", "-openshift-*"}, want: []*corev1.Event{user}},
+		{filter: "namespace", values: []string{"*", "-openshift-*"}, want: []*corev1.Event{kubeSystem, user}},
+		{filter: "namespace", values: []string{"openshift-*", "-openshift-etcd"}, want: []*corev1.Event{}},
+		{filter: "namespace", values: []string{"kube-system", "-kube-system"}, want: []*corev1.Event{}},
+		{filter: "namespace", values: []string{"kube-system", "-user"}, want: []*corev1.Event{kubeSystem}},
+		{filter: "name", values: []string{"etcd*", "-etcd-0"}, want: []*corev1.Event{kubeSystem}},
+		{filter: "name", values: []string{"-web"}, want: []*corev1.Event{kubeSystem, openshift}},
+		{filter: "reason", values: []string{"Failed*", "-FailedMount"}, want: []*corev1.Event{user}},
+		{filter: "reason", values: []string{"-BackOff", "-FailedScheduling"}, want: []*corev1.Event{openshift}},
+		{filter: "component", values: []string{"-kubelet"}, want: []*corev1.Event{openshift, user}},
+		{filter: "component", values: []string{"kube-*", "-kubelet"}, want: []*corev1.Event{openshift}},
+		{filter: "uid", values: []string{"uid-*", "-uid-2"}, want: []*corev1.Event{kubeSystem, user}},
+		{filter: "uid", values: []string{"-uid-1", "-uid-3"}, want: []*corev1.Event{openshift}},
+	}
+	for _, test := range tests {
+		got := filters[test.filter](test.values...).FilterEvents(events...)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s %v: expected %v, got %v", test.filter, test.values, eventNames(test.want), eventNames(got))
+		}
+	}
+}
+
+// benchmarkEvents returns n events spread over namespaces, reasons and types, about half of them Warnings.
+func benchmarkEvents(n int) []*corev1.Event {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	namespaces := []string{"kube-system", "openshift-etcd", "openshift-apiserver", "user"}
+	reasons := []string{"BackOff", "FailedMount", "Scheduled", "Pulled", "Unhealthy"}
+	events := make([]*corev1.Event, 0, n)
+	for i := 0; i < n; i++ {
+		event := testEvent(namespaces[i%len(namespaces)], fmt.Sprintf("pod-%d", i%1000), reasons[i%len(reasons)], base.Add(time.Duration(i)*time.Second))
+		if i%2 == 0 {
+			event.Type = corev1.EventTypeNormal
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// benchmarkFilters returns a chain of predicates keeping some of the benchmarkEvents.
+func benchmarkFilters() EventFilters {
+	return EventFilters{
+		&FilterByWarnings{},
+		&FilterByNamespaces{Namespaces: sets.NewString("openshift-*", "user")},
+		&FilterByNames{Names: sets.NewString("-pod-1")},
+		NewFilterByReasons([]string{"BackOff", "FailedMount", "Unhealthy"}, false, false),
+		&FilterByComponent{Components: sets.NewString("-kubelet")},
+	}
+}
+
+func BenchmarkEventFilters(b *testing.B) {
+	events := benchmarkEvents(100000)
+
+	// the fused chain judges every event once, without intermediate slices
+	b.Run("fused", func(b *testing.B) {
+		filters := benchmarkFilters()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			filters.FilterEvents(events...)
+		}
+	})
+	// every filter on its own, as before the predicates were fused
+	b.Run("chained", func(b *testing.B) {
+		filters := benchmarkFilters()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ret := events
+			for _, filter := range filters {
+				ret = filter.FilterEvents(ret...)
+			}
+		}
+	})
+}
+func TestFilterByTimeRange(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	before := testEvent("ns", "before", "BackOff", base.Add(-time.Second))
+	start := testEvent("ns", "start", "BackOff", base)
+	middle := testEvent("ns", "middle", "BackOff", base.Add(30*time.Minute))
+	end := testEvent("ns", "end", "BackOff", base.Add(time.Hour))
+	after := testEvent("ns", "after", "BackOff", base.Add(time.Hour+time.Second))
+	events := []*corev1.Event{before, start, middle, end, after}
+
+	tests := []struct {
+		name     string
+		since    string
+		until    string
+		expected []string
+	}{
+		{name: "inclusive endpoints", since: "2020-01-01T12:00:00Z", until: "2020-01-01T13:00:00Z", expected: []string{start.Name, middle.Name, end.Name}},
+		{name: "single instant", since: "2020-01-01T12:00:00Z", until: "2020-01-01T12:00:00Z", expected: []string{start.Name}},
+		{name: "open end", since: "2020-01-01T13:00:00Z", expected: []string{end.Name, after.Name}},
+		{name: "open start", until: "2020-01-01T12:00:00Z", expected: []string{before.Name, start.Name}},
+		{name: "open", expected: []string{before.Name, start.Name, middle.Name, end.Name, after.Name}},
+		{name: "times of day", since: "12:00", until: "13:00", expected: []string{start.Name, middle.Name, end.Name}},
+		{name: "times of day with seconds", since: "11:59:59", until: "12:00:00", expected: []string{before.Name, start.Name}},
+		{name: "time of day and absolute time", since: "12:30", until: "2020-01-01T13:00:00Z", expected: []string{middle.Name, end.Name}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter, err := NewFilterByTimeRange(test.since, test.until)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := eventNames(filter.FilterEvents(events...)); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewFilterByTimeRangeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		since string
+		until string
+	}{
+		{name: "since after until", since: "2020-01-01T13:00:00Z", until: "2020-01-01T12:00:00Z"},
+		{name: "since after until as times of day", since: "13:00", until: "12:59:59"},
+		{name: "invalid since", since: "noon"},
+		{name: "invalid until", until: "12"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewFilterByTimeRange(test.since, test.until); err == nil {
+				t.Errorf("expected an error for since %q and until %q", test.since, test.until)
+			}
+		})
+	}
+}
+func TestFilterByKindMatches(t *testing.T) {
+	pod := schema.GroupKind{Kind: "Pod"}
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	statefulSet := schema.GroupKind{Group: "apps", Kind: "StatefulSet"}
+	operator := schema.GroupKind{Group: "config.openshift.io", Kind: "ClusterOperator"}
+	all := []schema.GroupKind{pod, deployment, statefulSet, operator}
+
+	tests := []struct {
+		name     string
+		kinds    []schema.GroupKind
+		expected []schema.GroupKind
+	}{
+		{name: "exact", kinds: []schema.GroupKind{deployment}, expected: []schema.GroupKind{deployment}},
+		{name: "exact in the core group", kinds: []schema.GroupKind{pod}, expected: []schema.GroupKind{pod}},
+		{name: "any group", kinds: []schema.GroupKind{{Group: "*", Kind: "Deployment"}}, expected: []schema.GroupKind{deployment}},
+		{name: "any kind", kinds: []schema.GroupKind{{Group: "apps", Kind: "*"}}, expected: []schema.GroupKind{deployment, statefulSet}},
+		{name: "anything", kinds: []schema.GroupKind{{Group: "*", Kind: "*"}}, expected: all},
+		{name: "excluded kind", kinds: []schema.GroupKind{{Group: "apps", Kind: "-Deployment"}}, expected: []schema.GroupKind{pod, statefulSet, operator}},
+		{name: "excluded kind in any group", kinds: []schema.GroupKind{{Group: "*", Kind: "-Pod"}}, expected: []schema.GroupKind{deployment, statefulSet, operator}},
+		{name: "everything excluded", kinds: []schema.GroupKind{{Group: "*", Kind: "-*"}}, expected: []schema.GroupKind{}},
+		{name: "excluded group", kinds: []schema.GroupKind{{Group: "apps", Kind: "-*"}}, expected: []schema.GroupKind{pod, operator}},
+		{name: "exclusion beats inclusion", kinds: []schema.GroupKind{deployment, {Group: "apps", Kind: "-Deployment"}}, expected: []schema.GroupKind{}},
+		{name: "exact exclusion beats any kind", kinds: []schema.GroupKind{{Group: "apps", Kind: "*"}, {Group: "apps", Kind: "-Deployment"}}, expected: []schema.GroupKind{statefulSet}},
+		{name: "exact inclusion beats excluded kind", kinds: []schema.GroupKind{deployment, {Group: "apps", Kind: "-*"}}, expected: []schema.GroupKind{deployment}},
+		{name: "exact inclusion beats everything excluded", kinds: []schema.GroupKind{pod, {Group: "*", Kind: "-*"}}, expected: []schema.GroupKind{pod}},
+		{name: "any kind beats everything excluded", kinds: []schema.GroupKind{{Group: "apps", Kind: "*"}, {Group: "*", Kind: "-*"}}, expected: []schema.GroupKind{deployment, statefulSet}},
+		{name: "inclusions without a match", kinds: []schema.GroupKind{{Group: "batch", Kind: "Job"}, {Group: "apps", Kind: "-Deployment"}}, expected: []schema.GroupKind{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filter := &FilterByKind{Kinds: map[schema.GroupKind]bool{}}
+			for _, kind := range test.kinds {
+				filter.Kinds[kind] = true
+			}
+			got := []schema.GroupKind{}
+			for _, gk := range all {
+				if filter.matches(gk) {
+					got = append(got, gk)
+				}
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+// countingFilter counts the calls to the filter it wraps, which it hides the Accept of, so EventFilters calls its
+// FilterEvents.
+type countingFilter struct {
+	filter EventFilter
+	calls  int
+}
+
+func (f *countingFilter) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	f.calls++
+	return f.filter.FilterEvents(events...)
+}
+
+func TestFilterChainsStopOnceEmpty(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	normal := testEvent("ns", "a", "Scheduled", base)
+	normal.Type = corev1.EventTypeNormal
+
+	namespaces := &countingFilter{filter: &FilterByNamespaces{Namespaces: sets.NewString("ns")}}
+	warnings := &countingFilter{filter: &FilterByWarnings{}}
+	dedup := &countingFilter{filter: &FilterByDedup{}}
+	predicateCalls := 0
+	filters := EventFilters{
+		namespaces,
+		warnings,
+		dedup,
+		&PredicateFilter{Predicate: EventPredicateFunc(func(*corev1.Event) bool {
+			predicateCalls++
+			return true
+		})},
+	}
+
+	if got := filters.FilterEvents(normal); len(got) != 0 {
+		t.Errorf("expected no events, got %v", eventNames(got))
+	}
+	if namespaces.calls != 1 || warnings.calls != 1 {
+		t.Errorf("expected the filters up to the one leaving no events to be called once, got %d and %d", namespaces.calls, warnings.calls)
+	}
+	if dedup.calls != 0 || predicateCalls != 0 {
+		t.Errorf("expected the filters after no events are left not to be called, got %d and %d", dedup.calls, predicateCalls)
+	}
+
+	// with no events to begin with no filter is called
+	namespaces.calls, warnings.calls = 0, 0
+	if _, err := filters.FilterEventsCtx(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if namespaces.calls != 0 || warnings.calls != 0 || dedup.calls != 0 || predicateCalls != 0 {
+		t.Errorf("expected no filter to be called without events, got %d, %d, %d and %d", namespaces.calls, warnings.calls, dedup.calls, predicateCalls)
+	}
+}
@@ -1,18 +1,19 @@
 package events
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"sort"
+	"os"
 	"strings"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
@@ -38,18 +39,38 @@ type EventOptions struct {
 	configFlags  *genericclioptions.ConfigFlags
 	builderFlags *genericclioptions.ResourceBuilderFlags
 
-	kinds          []string
-	namespaces     []string
-	names          []string
-	reasons        []string
-	components     []string
-	uids           []string
+	filterOptions FilterOptions
+
 	filename       string
-	warningOnly    bool
+	fromFile       string
+	fromMustGather string
+	fromEtcdDump   string
+	dedupSources   bool
 	output         string
+	columns        []string
 	sortBy         string
-	around         string
-	aroundDuration time.Duration
+	limit          int
+	countOnly      bool
+	warnUnmatched  bool
+	sumCount       bool
+	groupBy        string
+	histogram      time.Duration
+	timeline       bool
+	incidentGap    time.Duration
+	uniqueObjects  bool
+	showProgress   bool
+	invertMatch    bool
+	byReason       bool
+	watch          bool
+	color          string
+	showDelta      bool
+	failOnMatch    bool
+	enrich         bool
+	collapse       bool
+	// allNamespacesSet is set when --all-namespaces is given
+	allNamespacesSet bool
+	// wideHeaderWritten is set once the header of the wide output was written, so watching writes it once
+	wideHeaderWritten bool
 
 	genericclioptions.IOStreams
 }
@@ -95,17 +116,81 @@ func NewCmdEvent(parentName string, streams genericclioptions.IOStreams) *cobra.
 		},
 	}
 
-	cmd.Flags().StringVarP(&o.output, "output", "o", o.output, "Choose your output format")
-	cmd.Flags().StringSliceVar(&o.uids, "uid", o.uids, "Only match specific UIDs")
-	cmd.Flags().StringSliceVar(&o.kinds, "kinds", o.kinds, "Filter result of search to only contain the specified kind.)")
-	cmd.Flags().StringSliceVarP(&o.namespaces, "namespace", "n", o.namespaces, "Filter result of search to only contain the specified namespace.)")
-	cmd.Flags().StringSliceVar(&o.names, "name", o.names, "Filter result of search to only contain the specified name.)")
-	cmd.Flags().StringSliceVar(&o.reasons, "reason", o.reasons, "Filter result of search to only contain the specified reason.)")
-	cmd.Flags().StringSliceVar(&o.components, "component", o.components, "Filter result of search to only contain the specified component.)")
-	cmd.Flags().BoolVar(&o.warningOnly, "warning-only", false, "Filter result of search to only contain http failures.)")
+	cmd.Flags().StringVar(&o.fromFile, "from-file", o.fromFile, "Read the events from a JSON or YAML List, or newline-delimited JSON events, in the file ('-' for stdin) instead of --filename or the cluster")
+	cmd.Flags().StringVar(&o.fromEtcdDump, "from-etcd-dump", o.fromEtcdDump, "Read the events from a directory of etcd values, one file per key at the path of the key (e.g. <dir>/kubernetes.io/events/<namespace>/<name>), instead of --filename or the cluster")
+	cmd.Flags().StringVar(&o.fromMustGather, "from-must-gather", o.fromMustGather, "Read the events from the files of a must-gather directory instead of --filename or the cluster")
+	cmd.Flags().StringVarP(&o.output, "output", "o", o.output, "Choose your output format (wide, json, yaml, list, csv, tsv, components, series or custom-columns=<header>:<json-path>,...)")
+	cmd.Flags().StringVar(&o.color, "color", "auto", "Print Warning events in color: auto (when writing to a terminal and NO_COLOR is not set), always or never")
+	cmd.Flags().StringSliceVar(&o.columns, "columns", o.columns, "Columns written by -o csv and -o tsv, in order (lastTimestamp, count, type, reason, namespace, kind, name, component, message)")
+	cmd.Flags().StringSliceVar(&o.filterOptions.UIDs, "uid", o.filterOptions.UIDs, "Only match events about the objects with the specified UIDs (involvedObject.uid)")
+	cmd.Flags().StringSliceVar(&o.filterOptions.EventUIDs, "event-uid", o.filterOptions.EventUIDs, "Only match the events with the specified UIDs (metadata.uid of the event itself)")
+	cmd.Flags().StringSliceVar(&o.filterOptions.ResourceVersions, "resource-version", o.filterOptions.ResourceVersions, "Only match events recorded against the specified resource versions of their objects (involvedObject.resourceVersion)")
+	cmd.Flags().Uint64Var(&o.filterOptions.MinResourceVersion, "rv-min", o.filterOptions.MinResourceVersion, "Only match events whose involvedObject.resourceVersion is a number at least this large")
+	cmd.Flags().Uint64Var(&o.filterOptions.MaxResourceVersion, "rv-max", o.filterOptions.MaxResourceVersion, "Only match events whose involvedObject.resourceVersion is a number at most this large")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Kinds, "kinds", o.filterOptions.Kinds, "Filter result of search to only contain the specified kind, as Kind, Kind.group or group/Kind, or a resource in place of the Kind (e.g. Pod, pods, Deployment.apps, apps/deployments, apps/*). Prefix with '-' to exclude.")
+	cmd.Flags().StringSliceVar(&o.filterOptions.APIVersions, "api-version", o.filterOptions.APIVersions, "Filter result of search to only contain objects of the specified API version, '*' matches any group or version (e.g. apps/*, */v1).")
+	cmd.Flags().StringSliceVarP(&o.filterOptions.Namespaces, "namespace", "n", o.filterOptions.Namespaces, "Filter result of search to only contain the specified namespace. The events of the cluster are read from the namespace of the current context unless --namespace or --all-namespaces is given")
+	cmd.Flags().BoolVar(&o.filterOptions.ClusterScoped, "cluster-scoped", o.filterOptions.ClusterScoped, "Filter result of search to only contain events about cluster-scoped objects, like nodes and persistent volumes")
+	cmd.Flags().BoolVar(&o.filterOptions.Namespaced, "namespaced", o.filterOptions.Namespaced, "Filter result of search to only contain events about namespaced objects")
+	cmd.Flags().StringArrayVar(&o.filterOptions.NameRegexps, "name-regex", o.filterOptions.NameRegexps, "Filter result of search to only contain objects whose name matches the regular expression (e.g. '^web-[a-z0-9]+-[a-z0-9]{5}$'), patterns are not anchored.")
+	cmd.Flags().StringArrayVar(&o.filterOptions.NamespaceRegexps, "namespace-regex", o.filterOptions.NamespaceRegexps, "Filter result of search to only contain namespaces matching the regular expression (e.g. '^e2e-test-build-', '^$' for cluster-scoped objects).")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Names, "name", o.filterOptions.Names, "Filter result of search to only contain the specified name.)")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Reasons, "reason", o.filterOptions.Reasons, "Filter result of search to only contain the specified reason.)")
+	cmd.Flags().BoolVar(&o.filterOptions.ReasonPrefix, "reason-prefix", o.filterOptions.ReasonPrefix, "Match the reasons each --reason value is a prefix of, e.g. --reason Failed matches FailedScheduling and FailedMount.")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Actions, "action", o.filterOptions.Actions, "Filter result of search to only contain events with the specified action (e.g. Binding), as set by the events.k8s.io API.")
+	cmd.Flags().StringArrayVar(&o.filterOptions.Messages, "message", o.filterOptions.Messages, "Filter result of search to only contain messages containing the specified text (case-insensitive). Prefix with '-' to exclude.")
+	cmd.Flags().BoolVar(&o.filterOptions.MessageRegexp, "message-regex", o.filterOptions.MessageRegexp, "Treat --message values as regular expressions (e.g. '^Failed to pull.*').")
+	cmd.Flags().BoolVar(&o.filterOptions.IgnoreCase, "ignore-case", o.filterOptions.IgnoreCase, "Match --reason values and --message regular expressions case-insensitively.")
+	cmd.Flags().StringSliceVar(&o.filterOptions.RelatedKinds, "related-kind", o.filterOptions.RelatedKinds, "Filter result of search to only contain events related to an object of the specified kind (e.g. ReplicaSet).")
+	cmd.Flags().StringSliceVar(&o.filterOptions.RelatedNames, "related-name", o.filterOptions.RelatedNames, "Filter result of search to only contain events related to an object of the specified name.")
+	cmd.Flags().StringSliceVar(&o.filterOptions.RelatedNamespaces, "related-namespace", o.filterOptions.RelatedNamespaces, "Filter result of search to only contain events related to an object in the specified namespace.")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Components, "component", o.filterOptions.Components, "Filter result of search to only contain the specified component.)")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Hosts, "host", o.filterOptions.Hosts, "Filter result of search to only contain events reported from the specified host.)")
+	cmd.Flags().StringSliceVar(&o.filterOptions.ReportingInstances, "reporting-instance", o.filterOptions.ReportingInstances, "Filter result of search to only contain events from the specified reporting instance of a controller (e.g. a scheduler replica).")
+	cmd.Flags().StringSliceVar(&o.filterOptions.FieldPaths, "field-path", o.filterOptions.FieldPaths, "Filter result of search to only contain events about the specified field path of the object (e.g. spec.containers{istio-proxy}).")
+	cmd.Flags().StringVar(&o.filterOptions.FieldSelector, "field-selector", o.filterOptions.FieldSelector, "Filter result of search with a field selector, as supported by kubectl get events (e.g. --field-selector involvedObject.kind=Pod,type=Warning)")
+	cmd.Flags().BoolVar(&o.filterOptions.WarningOnly, "warning-only", false, "Filter result of search to only contain warnings, the same as --type=Warning.")
+	cmd.Flags().BoolVar(&o.filterOptions.HideBenign, "hide-benign", o.filterOptions.HideBenign, fmt.Sprintf("Hide the Normal events with the reasons of healthy workloads (%s) or --benign-reason, Warning events are always shown", strings.Join(DefaultBenignReasons, ", ")))
+	cmd.Flags().StringSliceVar(&o.filterOptions.BenignReasons, "benign-reason", o.filterOptions.BenignReasons, "Reasons hidden by --hide-benign instead of the default ones")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Types, "type", o.filterOptions.Types, "Filter result of search to only contain events of the specified type (Normal or Warning). Prefix with '-' to exclude.")
+	cmd.Flags().BoolVar(&o.filterOptions.Dedup, "dedup", o.filterOptions.Dedup, "Collapse events about the same object with the same reason and message into one event.")
+	cmd.Flags().BoolVar(&o.dedupSources, "dedup-sources", o.dedupSources, "Drop the copies of an event read from more than one file, keeping the one with the highest count.")
+	cmd.Flags().Int32Var(&o.filterOptions.MinCount, "min-count", o.filterOptions.MinCount, "Filter result of search to only contain events observed at least this many times.")
+	cmd.Flags().DurationVar(&o.filterOptions.MinSpan, "min-span", o.filterOptions.MinSpan, "Filter result of search to only contain events repeating over at least this long between their first and last timestamp (e.g. 10m).")
+	cmd.Flags().Float64Var(&o.filterOptions.MinRatePerMinute, "min-rate", o.filterOptions.MinRatePerMinute, "Filter result of search to only contain events of objects and reasons firing more than this many times a minute over the time they span.")
+	cmd.Flags().IntVar(&o.filterOptions.MinObjects, "min-objects", o.filterOptions.MinObjects, "Filter result of search to only contain events whose reason is reported about at least this many distinct objects.")
+	cmd.Flags().Int32Var(&o.filterOptions.MaxCount, "max-count", o.filterOptions.MaxCount, "Filter result of search to only contain events observed at most this many times.")
+	cmd.Flags().IntVar(&o.filterOptions.MinMessageLength, "min-msg-len", o.filterOptions.MinMessageLength, "Filter result of search to only contain events with a message at least this many bytes long.")
+	cmd.Flags().IntVar(&o.filterOptions.MaxMessageLength, "max-msg-len", o.filterOptions.MaxMessageLength, "Filter result of search to only contain events with a message at most this many bytes long.")
+	cmd.Flags().StringVar(&o.groupBy, "group-by", o.groupBy, "Print the total count of the events by reason, namespace, kind, component or message, with names, addresses and numbers in messages replaced by placeholders, instead of the events")
+	cmd.Flags().DurationVar(&o.histogram, "histogram", o.histogram, "Print the number of events in each interval of the specified width (e.g. 1m) instead of the events")
+	cmd.Flags().BoolVar(&o.timeline, "timeline", o.timeline, "Print the number of events over time instead of the events, in intervals picked from the time they span unless --histogram is set")
+	cmd.Flags().BoolVar(&o.uniqueObjects, "unique-objects", o.uniqueObjects, "Print the distinct involved objects of the events, as kind/namespace/name with the total count of their events, instead of the events")
+	cmd.Flags().DurationVar(&o.incidentGap, "incidents", o.incidentGap, "Print the incidents, bursts of events separated by quiet periods longer than the specified duration (e.g. 5m), instead of the events")
+	cmd.Flags().BoolVar(&o.byReason, "timeline-by-reason", o.byReason, "Split --timeline and --histogram by reason")
+	cmd.Flags().StringVar(&o.sortBy, "sort-by", o.sortBy, "Sort the result by time (the best timestamp of each event, default), lastTimestamp, firstTimestamp, count, rate or reason")
+	cmd.Flags().BoolVar(&o.warnUnmatched, "warn-unmatched", o.warnUnmatched, "Report the filter values that matched no event on stderr")
+	cmd.Flags().BoolVar(&o.countOnly, "count-only", o.countOnly, "Print only the number of events that matched")
+	cmd.Flags().BoolVar(&o.sumCount, "sum-count", o.sumCount, "With --count-only, print the summed count of the events that matched instead")
+	cmd.Flags().IntVar(&o.limit, "limit", o.limit, "Print at most this many events, or lines of the default output, the last ones in sort order (e.g. the most recent, or the noisiest with --sort-by count)")
 	cmd.Flags().StringVar(&o.sortBy, "by", o.sortBy, "Choose how to sort")
-	cmd.Flags().StringVar(&o.around, "around", o.around, "Display only events around specified time (format: hh:mm or hh:mm:ss)")
-	cmd.Flags().DurationVar(&o.aroundDuration, "around-duration", 10*time.Minute, "Change the time duration to display events around time")
+	cmd.Flags().MarkDeprecated("by", "use --sort-by instead")
+	cmd.Flags().StringSliceVar(&o.filterOptions.Arounds, "around", o.filterOptions.Arounds, "Display only events around specified times (format: hh:mm, hh:mm:ss, now or now-<duration>, optionally followed by /<duration> to override --around-duration), may be repeated to combine several windows")
+	cmd.Flags().DurationVar(&o.filterOptions.AroundDuration, "around-duration", 10*time.Minute, "Change the time duration to display events around time")
+	cmd.Flags().StringVar(&o.filterOptions.Since, "since", o.filterOptions.Since, "Display only events newer than a relative duration like 30m, 1h or 2d, as with kubectl logs. Use --since-time for an absolute time")
+	cmd.Flags().StringVar(&o.filterOptions.SinceTime, "since-time", o.filterOptions.SinceTime, "Display only events last seen at or after the specified time (format: RFC3339, hh:mm or hh:mm:ss)")
+	cmd.Flags().StringVar(&o.filterOptions.Until, "until", o.filterOptions.Until, "Display only events last seen at or before the specified time (format: RFC3339, hh:mm or hh:mm:ss)")
+	cmd.Flags().BoolVar(&o.collapse, "collapse", o.collapse, "Print the events about the same object with the same type, reason and message as one line with their summed count, first and last seen time, like kubectl get events")
+	cmd.Flags().BoolVar(&o.showDelta, "show-delta", o.showDelta, "Prefix every event with the +MM:SS since the previous one, to show the quiet periods between bursts of events")
+	cmd.Flags().BoolVar(&o.enrich, "enrich", o.enrich, "Fetch the involved objects of the events from the cluster, once per object, to filter them with --selector or --annotation-selector. Objects deleted since are skipped")
+	cmd.Flags().StringVarP(&o.filterOptions.Selector, "selector", "l", o.filterOptions.Selector, "Filter result of search to only contain events whose involved object has matching labels (e.g. -l app=foo), requires --enrich")
+	cmd.Flags().StringVar(&o.filterOptions.AnnotationSelector, "annotation-selector", o.filterOptions.AnnotationSelector, "Filter result of search to only contain events whose involved object has matching annotations, in label selector syntax, requires --enrich")
+	cmd.Flags().BoolVar(&o.showProgress, "progress", o.showProgress, "Report the events read and matched so far on stderr, by default only when stderr is a terminal")
+	cmd.Flags().BoolVarP(&o.invertMatch, "invert-match", "v", o.invertMatch, "Select the events the filters do not match, like grep -v")
+	cmd.Flags().BoolVar(&o.failOnMatch, "fail-on-match", o.failOnMatch, "Exit with status 1 when any event matches the filters, e.g. with --warning-only to fail a CI job on Warning events")
+	cmd.Flags().BoolVarP(&o.watch, "watch", "w", o.watch, "Watch the events of the cluster and print the ones that match as they arrive")
+	cmd.Flags().DurationVar(&o.filterOptions.MaxAge, "max-age", o.filterOptions.MaxAge, "Display only events last seen within the specified duration (e.g. 30m) of the newest event, rather than of the current time, so a dump gives the same result on every run")
+	cmd.Flags().StringVar(&o.filterOptions.AroundTimeZone, "around-tz", o.filterOptions.AroundTimeZone, "Time zone the --around time is given in (e.g. UTC, America/New_York), defaults to UTC")
 
 	o.configFlags.AddFlags(cmd.Flags())
 	o.builderFlags.AddFlags(cmd.Flags())
@@ -114,15 +199,264 @@ func NewCmdEvent(parentName string, streams genericclioptions.IOStreams) *cobra.
 }
 
 func (o *EventOptions) Complete(command *cobra.Command, args []string) error {
+	// --all-namespaces defaults to true so files are read whole, only asking for it selects every namespace of the
+	// cluster, whatever --namespace says, like kubectl
+	o.allNamespacesSet = command.Flags().Changed("all-namespaces") && *o.builderFlags.AllNamespaces
+	if o.allNamespacesSet {
+		o.filterOptions.Namespaces = nil
+	}
 
 	return nil
 }
 
 func (o *EventOptions) Validate() error {
+	switch o.color {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("--color must be one of auto, always or never")
+	}
+	if o.sumCount && !o.countOnly {
+		return fmt.Errorf("--sum-count can only be used with --count-only")
+	}
+	if len(o.filterOptions.BenignReasons) > 0 && !o.filterOptions.HideBenign {
+		return fmt.Errorf("--benign-reason can only be used with --hide-benign")
+	}
+	// the collapsed copies of --dedup are never among the events read, so every event would be inverted in
+	if o.invertMatch && o.filterOptions.Dedup {
+		return fmt.Errorf("--invert-match cannot be used with --dedup")
+	}
+	if o.filterOptions.MaxResourceVersion > 0 && o.filterOptions.MinResourceVersion > o.filterOptions.MaxResourceVersion {
+		return fmt.Errorf("--rv-min must not be larger than --rv-max")
+	}
+	if o.filterOptions.ClusterScoped && o.filterOptions.Namespaced {
+		return fmt.Errorf("only one of --cluster-scoped or --namespaced may be used")
+	}
+	if o.collapse {
+		if o.output != "" && o.output != "wide" {
+			return fmt.Errorf("--collapse only supports the default and wide output formats")
+		}
+		if o.showDelta || o.watch {
+			return fmt.Errorf("--collapse cannot be used with --show-delta or --watch")
+		}
+	}
+	if o.showDelta {
+		if o.sortBy != "" && o.sortBy != "time" {
+			return fmt.Errorf("--show-delta requires the events to be sorted by time")
+		}
+		if o.output != "" {
+			return fmt.Errorf("--show-delta only supports the default output format")
+		}
+		if o.watch {
+			return fmt.Errorf("--show-delta cannot be used with --watch")
+		}
+	}
+	if (len(o.filterOptions.Selector) > 0 || len(o.filterOptions.AnnotationSelector) > 0) && !o.enrich {
+		return fmt.Errorf("--selector and --annotation-selector require --enrich to fetch the involved objects")
+	}
+	sources := 0
+	for _, source := range []bool{len(*o.builderFlags.FileNameFlags.Filenames) > 0, len(o.fromFile) > 0, len(o.fromMustGather) > 0, len(o.fromEtcdDump) > 0, o.watch} {
+		if source {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("only one of --filename, --from-file, --from-must-gather, --from-etcd-dump or --watch may be used")
+	}
+	if o.watch {
+		if len(o.filterOptions.arounds()) > 0 || len(o.filterOptions.Since) > 0 || len(o.filterOptions.SinceTime) > 0 || len(o.filterOptions.Until) > 0 || o.filterOptions.MaxAge > 0 {
+			return fmt.Errorf("--around, --since, --since-time, --until and --max-age cannot be used with --watch")
+		}
+		if o.filterOptions.MinRatePerMinute > 0 || o.filterOptions.MinObjects > 0 {
+			return fmt.Errorf("--min-rate and --min-objects cannot be used with --watch")
+		}
+		if o.filterOptions.Dedup || o.dedupSources || len(o.groupBy) > 0 || o.timeline || o.histogram > 0 || o.incidentGap > 0 || o.uniqueObjects || o.limit > 0 || o.countOnly {
+			return fmt.Errorf("--dedup, --dedup-sources, --group-by, --timeline, --histogram, --incidents, --unique-objects, --limit and --count-only cannot be used with --watch")
+		}
+		if o.failOnMatch {
+			return fmt.Errorf("--fail-on-match cannot be used with --watch")
+		}
+		if o.output != "" && o.output != "wide" {
+			return fmt.Errorf("--watch only supports the default and wide output formats")
+		}
+	}
 	return nil
 }
 
 func (o *EventOptions) Run() error {
+	progress := o.newProgress()
+	if o.enrich {
+		lookup, lookups := NewInvolvedObjectLookup(o.configFlags), 0
+		o.filterOptions.Lookup = func(ref corev1.ObjectReference) (metav1.Object, error) {
+			lookups++
+			progress.report("Looked up %d involved objects", lookups)
+			return lookup(ref)
+		}
+	}
+	filters, err := BuildFilters(o.filterOptions)
+	if err != nil {
+		return err
+	}
+
+	if o.watch {
+		return o.runWatch(o.invert(filters))
+	}
+
+	events, err := o.readEvents(progress)
+	if err != nil {
+		return err
+	}
+	read := len(events)
+
+	if len(o.filterOptions.Reasons) > 0 && !o.filterOptions.IgnoreCase {
+		for _, reason := range o.filterOptions.Reasons {
+			if suggestion, ok := suggestReason(events, reason); ok {
+				fmt.Fprintf(o.ErrOut, "No events have reason %q, did you mean %q? Use --ignore-case to match reasons regardless of case.\n", reason, suggestion)
+			}
+		}
+	}
+	if o.dedupSources {
+		events = DedupEvents(events)
+	}
+	// against every event read, an earlier filter must not hide the events a later value matches
+	unmatched := map[string][]string{}
+	if o.warnUnmatched {
+		unmatched = filters.UnmatchedValues(events)
+	}
+	events, err = o.invert(filters).FilterEventsCtx(context.Background(), events...)
+	if err != nil {
+		return err
+	}
+	progress.done("Matched %d of %d events", len(events), read)
+	for _, field := range sets.StringKeySet(unmatched).List() {
+		fmt.Fprintf(o.ErrOut, "No events matched --%s %s\n", field, strings.Join(unmatched[field], ", "))
+	}
+
+	if err := o.printResult(events); err != nil {
+		return err
+	}
+	// the events are printed before failing, so a failed check shows what it found
+	if o.failOnMatch && len(events) > 0 {
+		return fmt.Errorf("%d events matched", len(events))
+	}
+	return nil
+}
+
+// printResult prints the events that matched the filters in the format selected by the flags.
+func (o *EventOptions) printResult(events []*corev1.Event) error {
+	if o.countOnly {
+		total := len(events)
+		if o.sumCount {
+			total = 0
+			for _, event := range events {
+				total += eventCount(event)
+			}
+		}
+		_, err := fmt.Fprintln(o.Out, total)
+		return err
+	}
+
+	if len(o.groupBy) > 0 {
+		summary, err := Summarize(events, o.groupBy)
+		if err != nil {
+			return err
+		}
+		return PrintSummary(o.Out, o.groupBy, SortSummary(summary))
+	}
+
+	if o.uniqueObjects {
+		return PrintObjects(o.Out, UniqueInvolvedObjects(events))
+	}
+
+	if o.incidentGap > 0 {
+		return PrintIncidents(o.Out, ClusterByTimeGaps(events, o.incidentGap))
+	}
+
+	if o.timeline || o.histogram > 0 {
+		if o.byReason {
+			return RenderTimelineByReason(o.Out, events, o.histogram)
+		}
+		return RenderTimeline(o.Out, events, o.histogram)
+	}
+
+	if o.output == "series" {
+		return PrintSeries(o.Out, AggregateSeries(events))
+	}
+
+	// the default output prints repeated events twice, the copies are sorted in and count toward --limit like the lines
+	// they are, wide prints the count like kubectl
+	if o.output == "" && !o.collapse {
+		events = duplicateRepeatedEvents(events)
+	}
+	if err := SortEvents(events, o.sortBy); err != nil {
+		return err
+	}
+	// every sort is ascending, so the most recent or noisiest events are the last ones
+	if o.limit > 0 && len(events) > o.limit {
+		events = events[len(events)-o.limit:]
+	}
+
+	// collapsing is only for display, every other output still writes the individual events
+	if o.collapse {
+		return PrintDisplayRows(o.Out, CollapseForDisplay(events))
+	}
+
+	switch o.output {
+	case "components":
+		PrintComponents(o.Out, events)
+	case "", "wide":
+		if err := o.printEvents(events); err != nil {
+			return err
+		}
+	case "json", "yaml", "list":
+		if err := WriteEvents(o.Out, events, o.output); err != nil {
+			return err
+		}
+	case "csv":
+		if err := WriteEventsCSV(o.Out, events, o.columns); err != nil {
+			return err
+		}
+	case "tsv":
+		if err := WriteEventsTSV(o.Out, events, o.columns); err != nil {
+			return err
+		}
+	default:
+		if strings.HasPrefix(o.output, "custom-columns=") {
+			return PrintEventsCustomColumns(o.Out, events, strings.TrimPrefix(o.output, "custom-columns="))
+		}
+		return fmt.Errorf("unsupported output format")
+	}
+
+	return nil
+}
+
+// readEvents reads the events from --from-file, --from-must-gather or --from-etcd-dump, or from --filename or the cluster
+// through the resource builder.
+func (o *EventOptions) readEvents(progress *progress) ([]*corev1.Event, error) {
+	reportRead := func(events, files int) {
+		progress.report("Read %d events from %d files", events, files)
+	}
+	if len(o.fromFile) > 0 {
+		return loadEventsFromFile(o.fromFile, o.In, func(events, _ int) {
+			progress.report("Read %d events", events)
+		})
+	}
+	if len(o.fromEtcdDump) > 0 {
+		events, files, err := loadEventsFromEtcdDump(o.fromEtcdDump, reportRead)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(o.ErrOut, "Read %d events from %d files in %s\n", len(events), files, o.fromEtcdDump)
+		return events, nil
+	}
+	if len(o.fromMustGather) > 0 {
+		events, files, err := loadEventsFromMustGather(o.fromMustGather, reportRead)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(o.ErrOut, "Read %d events from %d files in %s\n", len(events), files, o.fromMustGather)
+		return events, nil
+	}
+
 	events := []*corev1.Event{}
 
 	visitor := o.builderFlags.ToBuilder(o.configFlags, nil).Do()
@@ -133,88 +467,138 @@ func (o *EventOptions) Run() error {
 
 		switch castObj := info.Object.(type) {
 		case *corev1.Event:
-			event := info.Object.(*corev1.Event)
-			events = append(events, event)
-
-			// inject the event twice when it appeared multiple times for easy sorting/reading
-			if event.LastTimestamp != event.FirstTimestamp {
-				alternateEvent := event.DeepCopy()
-				alternateEvent.FirstTimestamp = event.LastTimestamp
-				events = append(events, alternateEvent)
-			}
+			events = append(events, info.Object.(*corev1.Event))
+			progress.report("Read %d events", len(events))
 		default:
 			return fmt.Errorf("unhandled resource: %T", castObj)
 		}
 
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// invert returns the filters, or for --invert-match the filters matching the events they do not match.  The filters
+// themselves are kept so they can still report the values they did not match.
+func (o *EventOptions) invert(filters EventFilters) EventFilters {
+	if !o.invertMatch {
+		return filters
+	}
+	return EventFilters{&NotFilter{Delegate: filters}}
+}
+
+// runWatch prints the events of the cluster that match the filters as they arrive.
+func (o *EventOptions) runWatch(filters EventFilters) error {
+	namespace, allNamespaces, err := o.clusterNamespace()
 	if err != nil {
 		return err
 	}
 
-	filters := EventFilters{}
-	if len(o.around) > 0 {
-		filters = append(filters, &FilterByAround{Around: o.around, AroundDuration: o.aroundDuration})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := make(chan *corev1.Event)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- WatchFilteredEvents(ctx, o.configFlags, namespace, allNamespaces, filters, events)
+	}()
+
+	for event := range events {
+		if err := o.printEvents([]*corev1.Event{event}); err != nil {
+			return err
+		}
 	}
-	if len(o.uids) > 0 {
-		filters = append(filters, &FilterByUIDs{UIDs: sets.NewString(o.uids...)})
+	return <-watchErr
+}
+
+// clusterNamespace returns the namespace to read the events of the cluster from.  Like kubectl it defaults to the
+// namespace of the current kubeconfig context, a single --namespace overrides it and --all-namespaces reads every
+// namespace.  Several namespaces, exclusions or wildcards read every namespace and leave the choice to the filters.
+func (o *EventOptions) clusterNamespace() (string, bool, error) {
+	if o.allNamespacesSet {
+		return "", true, nil
 	}
-	if len(o.reasons) > 0 {
-		filters = append(filters, &FilterByReasons{Reasons: sets.NewString(o.reasons...)})
+	switch namespaces := o.filterOptions.Namespaces; {
+	case len(namespaces) == 1 && !strings.HasPrefix(namespaces[0], "-") && !strings.Contains(namespaces[0], "*"):
+		return namespaces[0], false, nil
+	case len(namespaces) > 0:
+		return "", true, nil
 	}
-	if len(o.names) > 0 {
-		filters = append(filters, &FilterByNames{Names: sets.NewString(o.names...)})
+	namespace, _, err := o.configFlags.ToRawKubeConfigLoader().Namespace()
+	if err != nil {
+		return "", false, err
 	}
-	if len(o.namespaces) > 0 {
-		filters = append(filters, &FilterByNamespaces{Namespaces: sets.NewString(o.namespaces...)})
+	return namespace, false, nil
+}
+
+// printEvents prints the events in the default format, in color when --color allows it and with the time since the
+// previous event for --show-delta, or in the wide format of kubectl with its header only once when watching.
+func (o *EventOptions) printEvents(events []*corev1.Event) error {
+	if o.output == "wide" {
+		header := !o.wideHeaderWritten
+		o.wideHeaderWritten = true
+		return writeEventsWide(o.Out, events, header, time.Now())
 	}
-	if len(o.kinds) > 0 {
-		kinds := map[schema.GroupKind]bool{}
-		for _, kind := range o.kinds {
-			parts := strings.Split(kind, ".")
-			gk := schema.GroupKind{}
-			gk.Kind = parts[0]
-			if len(parts) >= 2 {
-				gk.Group = strings.Join(parts[1:], ".")
-			}
-			kinds[gk] = true
-		}
+	printer := eventPrinter{color: o.useColor(), delta: o.showDelta}
+	return printer.print(o.Out, events)
+}
 
-		filters = append(filters, &FilterByKind{Kinds: kinds})
+// newProgress returns where to report progress, nil unless --progress is set or stderr is a terminal.
+func (o *EventOptions) newProgress() *progress {
+	file, ok := o.ErrOut.(*os.File)
+	isTerminal := ok && terminal.IsTerminal(int(file.Fd()))
+	if !o.showProgress && !isTerminal {
+		return nil
 	}
-	if len(o.components) > 0 {
-		filters = append(filters, &FilterByComponent{Components: sets.NewString(o.components...)})
+	return newProgress(o.ErrOut, isTerminal)
+}
+
+// useColor reports whether to print in color, by default only on a terminal and when NO_COLOR is not set.
+func (o *EventOptions) useColor() bool {
+	switch o.color {
+	case "always":
+		return true
+	case "never":
+		return false
 	}
-	if o.warningOnly {
-		filters = append(filters, &FilterByWarnings{})
+	if len(os.Getenv("NO_COLOR")) > 0 {
+		return false
 	}
+	file, ok := o.Out.(*os.File)
+	return ok && terminal.IsTerminal(int(file.Fd()))
+}
 
-	events = filters.FilterEvents(events...)
-
-	switch o.sortBy {
-	case "", "time":
-		sort.Sort(byTime(events))
-	case "count":
-		sort.Sort(byFrequency(events))
+// suggestReason returns a reason of the events that differs from the requested reason only in case, when no event has
+// the requested reason itself.  Exclusions and wildcards are not checked.
+func suggestReason(events []*corev1.Event, reason string) (string, bool) {
+	if strings.HasPrefix(reason, "-") || strings.Contains(reason, "*") {
+		return "", false
 	}
-
-	switch o.output {
-	case "components":
-		PrintComponents(o.Out, events)
-	case "":
-		PrintEvents(o.Out, events)
-	case "wide":
-		PrintEventsWide(o.Out, events)
-	case "json":
-		encoder := json.NewEncoder(o.Out)
-		for _, event := range events {
-			if err := encoder.Encode(event); err != nil {
-				return err
-			}
+	suggestion := ""
+	for _, event := range events {
+		if event.Reason == reason {
+			return "", false
+		}
+		if len(suggestion) == 0 && strings.EqualFold(event.Reason, reason) {
+			suggestion = event.Reason
 		}
-	default:
-		return fmt.Errorf("unsupported output format")
 	}
+	return suggestion, len(suggestion) > 0
+}
 
-	return nil
+// duplicateRepeatedEvents injects the event twice when it appeared multiple times for easy sorting/reading
+func duplicateRepeatedEvents(events []*corev1.Event) []*corev1.Event {
+	ret := make([]*corev1.Event, 0, len(events))
+	for _, event := range events {
+		ret = append(ret, event)
+		if event.LastTimestamp != event.FirstTimestamp {
+			alternateEvent := event.DeepCopy()
+			alternateEvent.FirstTimestamp = event.LastTimestamp
+			ret = append(ret, alternateEvent)
+		}
+	}
+	return ret
 }
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+func TestPrintResultLimit(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	repeated := testEvent("ns", "a", "BackOff", base)
+	repeated.Count = 5
+	repeated.LastTimestamp = metav1.NewTime(base.Add(10 * time.Minute))
+	once := testEvent("ns", "b", "FailedMount", base.Add(5*time.Minute))
+
+	tests := []struct {
+		output string
+		limit  int
+		want   int
+	}{
+		{output: "", limit: 0, want: 3},
+		{output: "", limit: 1, want: 1},
+		{output: "", limit: 2, want: 2},
+		{output: "wide", limit: 1, want: 2},
+		{output: "json", limit: 1, want: 1},
+	}
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		o := &EventOptions{output: test.output, limit: test.limit, IOStreams: genericclioptions.IOStreams{Out: out}}
+		if err := o.printResult([]*corev1.Event{repeated, once}); err != nil {
+			t.Fatal(err)
+		}
+
+		got := 0
+		switch test.output {
+		case "json":
+			got = strings.Count(out.String(), `"kind": "Event"`)
+		default:
+			got = strings.Count(out.String(), "\n")
+		}
+		if got != test.want {
+			t.Errorf("-o %q --limit %d: expected %d, got %d in:\n%s", test.output, test.limit, test.want, got, out.String())
+		}
+	}
+}
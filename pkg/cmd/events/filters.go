@@ -0,0 +1,303 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// FilterOptions selects the events to keep.  Empty options keep every event, string values accept the "-" exclusions
+// and "*" wildcards of util.AcceptString unless noted otherwise.
+type FilterOptions struct {
+	// Around is an HH:MM[:SS] time, now or now-<duration>, matching events within AroundDuration of it
+	Around string
+	// Arounds are more around times, the events around any of them match, each may be followed by /<duration> to
+	// use instead of AroundDuration
+	Arounds        []string
+	AroundDuration time.Duration
+	// AroundTimeZone is the time zone of an HH:MM[:SS] Around time, defaults to UTC
+	AroundTimeZone string
+	// Since is a duration, as parsed by ParseSince, matching events within it of the current time
+	Since string
+	// SinceTime and Until are RFC3339 or HH:MM[:SS] times bounding the events
+	SinceTime string
+	Until     string
+	// MaxAge matches events within MaxAge of the newest event
+	MaxAge time.Duration
+
+	// UIDs are the UIDs of involved objects, EventUIDs the UIDs of the events themselves
+	UIDs      []string
+	EventUIDs []string
+	// ResourceVersions are resource versions of involved objects, MinResourceVersion and MaxResourceVersion bound them
+	// as numbers, a zero bound leaves that side open
+	ResourceVersions   []string
+	MinResourceVersion uint64
+	MaxResourceVersion uint64
+	Reasons            []string
+	// ReasonPrefix matches the reasons each of the Reasons is a prefix of
+	ReasonPrefix bool
+	// Actions are the actions of the events, see FilterByAction
+	Actions []string
+	// Messages are case-insensitive substrings, or regular expressions when MessageRegexp is set
+	Messages      []string
+	MessageRegexp bool
+	// MinMessageLength and MaxMessageLength bound the length of the messages, a MaxMessageLength of zero leaves it
+	// unbounded
+	MinMessageLength int
+	MaxMessageLength int
+	// IgnoreCase matches Reasons and regular expression Messages regardless of case
+	IgnoreCase bool
+	Names      []string
+	// NameRegexps are unanchored regular expressions matching the name of the involved object
+	NameRegexps []string
+	Namespaces  []string
+	// ClusterScoped matches only events about cluster-scoped objects, Namespaced only the others
+	ClusterScoped bool
+	Namespaced    bool
+	// NamespaceRegexps are unanchored regular expressions matching the namespace of the involved object
+	NamespaceRegexps []string
+	// Kinds are Kind, Kind.group or group/Kind values, or resources in their place, resolved with KindMapper, see
+	// ParseKindFilterWithMapper
+	Kinds []string
+	// KindMapper resolves the Kinds, defaults to the kinds built into Kubernetes
+	KindMapper meta.RESTMapper
+	// APIVersions are group/version values, where "*" stands for any group or version
+	APIVersions []string
+	// RelatedKinds, RelatedNames and RelatedNamespaces match the related object of the events, like the ReplicaSet of
+	// a Pod, and exclude the events without one
+	RelatedKinds      []string
+	RelatedNames      []string
+	RelatedNamespaces []string
+	Components        []string
+	Hosts             []string
+	// ReportingInstances match ReportingInstance alone, where Hosts also match Source.Host
+	ReportingInstances []string
+	FieldPaths         []string
+	// FieldSelector is a field selector as supported by kubectl get events
+	FieldSelector string
+	// MinCount and MaxCount bound the count of the events, a MaxCount of zero leaves it unbounded
+	MinCount int32
+	MaxCount int32
+	// MinObjects keeps the events whose reason is reported about at least this many distinct objects, counting only the
+	// objects of the events every other option keeps
+	MinObjects int
+	// MinRatePerMinute keeps the events of the objects and reasons firing more often among the events every other
+	// option keeps, see FilterByRate
+	MinRatePerMinute float64
+	// MinSpan keeps the events whose FirstTimestamp and LastTimestamp are at least this far apart, the chronic ones
+	MinSpan time.Duration
+	// Types are event types, Normal or Warning, WarningOnly is a shorthand for Warning
+	Types       []string
+	WarningOnly bool
+	// Selector and AnnotationSelector are label selectors on the labels and annotations of the involved objects, which
+	// events do not carry, so they are fetched with Lookup.  Without a Lookup no event matches them.
+	Selector           string
+	AnnotationSelector string
+	Lookup             InvolvedObjectLookup
+	// HideBenign drops the Normal events with one of the BenignReasons, DefaultBenignReasons when empty
+	HideBenign    bool
+	BenignReasons []string
+	// Dedup collapses the recurrences of an event after the other filters on single events, the count, span, spread and
+	// rate filters see the collapsed events
+	Dedup bool
+
+	// Clock tells the current time to the filters on time relative to now, defaults to the RealClock
+	Clock Clock
+}
+
+// arounds returns Around and Arounds together.
+func (opts FilterOptions) arounds() []string {
+	arounds := []string{}
+	if len(opts.Around) > 0 {
+		arounds = append(arounds, opts.Around)
+	}
+	return append(arounds, opts.Arounds...)
+}
+
+// BuildFilters builds the filters selected by the options, in the order they are applied, so the filtering of the
+// event command can be reused without its flags.  Invalid options, like a malformed around time or regular
+// expression, are returned as errors.
+func BuildFilters(opts FilterOptions) (EventFilters, error) {
+	filters := EventFilters{}
+	if arounds := opts.arounds(); len(arounds) > 0 {
+		filter, err := NewFilterByArounds(arounds, opts.AroundDuration)
+		if err != nil {
+			return nil, err
+		}
+		if len(opts.AroundTimeZone) > 0 {
+			location, err := time.LoadLocation(opts.AroundTimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid around time zone: %v", err)
+			}
+			filter.Location = location
+		}
+		filter.Clock = opts.Clock
+		filters = append(filters, filter)
+	}
+	if len(opts.Since) > 0 {
+		since, err := ParseSince(opts.Since)
+		if err != nil {
+			return nil, err
+		}
+		if since > 0 {
+			filters = append(filters, &FilterBySince{Since: since, Clock: opts.Clock})
+		}
+	}
+	if len(opts.SinceTime) > 0 || len(opts.Until) > 0 {
+		filter, err := NewFilterByTimeRange(opts.SinceTime, opts.Until)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if opts.MaxAge > 0 {
+		filters = append(filters, &FilterByAge{MaxAge: opts.MaxAge, FromNewest: true, Clock: opts.Clock})
+	}
+	if len(opts.UIDs) > 0 {
+		filters = append(filters, &FilterByUIDs{UIDs: sets.NewString(opts.UIDs...)})
+	}
+	if len(opts.EventUIDs) > 0 {
+		filters = append(filters, &FilterByEventUIDs{UIDs: sets.NewString(opts.EventUIDs...)})
+	}
+	if len(opts.ResourceVersions) > 0 {
+		filters = append(filters, &FilterByResourceVersion{ResourceVersions: sets.NewString(opts.ResourceVersions...)})
+	}
+	if opts.MinResourceVersion > 0 || opts.MaxResourceVersion > 0 {
+		filters = append(filters, &FilterByResourceVersionRange{Min: opts.MinResourceVersion, Max: opts.MaxResourceVersion})
+	}
+	if len(opts.Reasons) > 0 {
+		filters = append(filters, NewFilterByReasons(opts.Reasons, opts.IgnoreCase, opts.ReasonPrefix))
+	}
+	if len(opts.Actions) > 0 {
+		filters = append(filters, &FilterByAction{Actions: sets.NewString(opts.Actions...)})
+	}
+	if len(opts.Messages) > 0 {
+		filter, err := NewFilterByMessage(opts.Messages, opts.MessageRegexp, opts.IgnoreCase)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if opts.MinMessageLength > 0 || opts.MaxMessageLength > 0 {
+		filters = append(filters, &FilterByMessageLength{MinLen: opts.MinMessageLength, MaxLen: opts.MaxMessageLength})
+	}
+	if len(opts.Names) > 0 {
+		filters = append(filters, &FilterByNames{Names: sets.NewString(opts.Names...)})
+	}
+	if len(opts.NameRegexps) > 0 {
+		filter, err := NewFilterByNameRegex(opts.NameRegexps)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(opts.Namespaces) > 0 {
+		filters = append(filters, &FilterByNamespaces{Namespaces: sets.NewString(opts.Namespaces...)})
+	}
+	if opts.ClusterScoped {
+		filters = append(filters, &FilterByClusterScoped{})
+	}
+	if opts.Namespaced {
+		filters = append(filters, &FilterByClusterScoped{Namespaced: true})
+	}
+	if len(opts.NamespaceRegexps) > 0 {
+		filter, err := NewFilterByNamespaceRegex(opts.NamespaceRegexps)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(opts.Kinds) > 0 {
+		mapper := opts.KindMapper
+		if mapper == nil {
+			mapper = builtinKindMapper()
+		}
+		kinds, err := ParseKindFilterWithMapper(opts.Kinds, mapper)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, &FilterByKind{Kinds: kinds})
+	}
+	if len(opts.APIVersions) > 0 {
+		apiVersions, err := ParseAPIVersions(opts.APIVersions)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, &FilterByAPIVersion{APIVersions: apiVersions})
+	}
+	if len(opts.RelatedKinds) > 0 || len(opts.RelatedNames) > 0 || len(opts.RelatedNamespaces) > 0 {
+		filters = append(filters, &FilterByRelated{
+			Kinds:      sets.NewString(opts.RelatedKinds...),
+			Names:      sets.NewString(opts.RelatedNames...),
+			Namespaces: sets.NewString(opts.RelatedNamespaces...),
+		})
+	}
+	if len(opts.Components) > 0 {
+		filters = append(filters, &FilterByComponent{Components: sets.NewString(opts.Components...)})
+	}
+	if len(opts.Hosts) > 0 {
+		filters = append(filters, &FilterByHosts{Hosts: sets.NewString(opts.Hosts...)})
+	}
+	if len(opts.ReportingInstances) > 0 {
+		filters = append(filters, &FilterByReportingInstance{Instances: sets.NewString(opts.ReportingInstances...)})
+	}
+	if len(opts.FieldPaths) > 0 {
+		filters = append(filters, &FilterByFieldPath{FieldPaths: sets.NewString(opts.FieldPaths...)})
+	}
+	if len(opts.FieldSelector) > 0 {
+		filter, err := NewFilterByFieldSelector(opts.FieldSelector)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	if len(opts.Types) > 0 {
+		filters = append(filters, &FilterByType{Types: sets.NewString(opts.Types...)})
+	}
+	if opts.WarningOnly {
+		filters = append(filters, &FilterByType{Types: sets.NewString(corev1.EventTypeWarning)})
+	}
+	if opts.HideBenign {
+		filters = append(filters, NewFilterByBenign(opts.BenignReasons))
+	}
+	// the involved objects are fetched after the cheaper filters on single events, so only the objects of the remaining
+	// events are looked up
+	if len(opts.Selector) > 0 {
+		selector, err := labels.Parse(opts.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selector: %v", err)
+		}
+		filters = append(filters, &FilterByInvolvedObjectLabels{Selector: selector, Lookup: opts.Lookup})
+	}
+	if len(opts.AnnotationSelector) > 0 {
+		selector, err := labels.Parse(opts.AnnotationSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid annotation selector: %v", err)
+		}
+		filters = append(filters, &FilterByInvolvedObjectAnnotations{Selector: selector, Lookup: opts.Lookup})
+	}
+	// dedup after the other filters on single events so the collapsed counts reflect only the events that matched, and
+	// before the filters on counts and spans so those see the collapsed counts
+	if opts.Dedup {
+		filters = append(filters, &FilterByDedup{})
+	}
+	if opts.MinCount > 0 || opts.MaxCount > 0 {
+		filters = append(filters, &FilterByCount{MinCount: opts.MinCount, MaxCount: opts.MaxCount})
+	}
+	if opts.MinSpan > 0 {
+		filters = append(filters, &FilterByFlapping{MinSpan: opts.MinSpan})
+	}
+	// the filters on every event at once come last, so they judge the same events that are returned
+	if opts.MinObjects > 0 {
+		filters = append(filters, &FilterByObjectSpread{MinObjects: opts.MinObjects})
+	}
+	if opts.MinRatePerMinute > 0 {
+		filters = append(filters, &FilterByRate{MinRatePerMinute: opts.MinRatePerMinute})
+	}
+
+	return filters, nil
+}
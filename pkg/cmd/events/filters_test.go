@@ -0,0 +1,255 @@
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestBuildFiltersDedupBeforeCount(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []*corev1.Event{}
+	for i := 0; i < 30; i++ {
+		events = append(events, testEvent("ns", "pod", "BackOff", base.Add(time.Duration(i)*time.Minute)))
+	}
+	events = append(events, testEvent("ns", "other", "BackOff", base))
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []int32
+	}{
+		{
+			name: "min count alone",
+			opts: FilterOptions{MinCount: 20},
+			want: []int32{},
+		},
+		{
+			name: "dedup sums the counts the min count checks",
+			opts: FilterOptions{MinCount: 20, Dedup: true},
+			want: []int32{30},
+		},
+		{
+			name: "dedup sums the counts the max count checks",
+			opts: FilterOptions{MaxCount: 20, Dedup: true},
+			want: []int32{1},
+		},
+		{
+			name: "dedup widens the span the min span checks",
+			opts: FilterOptions{MinSpan: 20 * time.Minute, Dedup: true},
+			want: []int32{30},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := BuildFilters(test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := filters.FilterEvents(events...)
+			if len(got) != len(test.want) {
+				t.Fatalf("expected %d events, got %d", len(test.want), len(got))
+			}
+			for i := range got {
+				if got[i].Count != test.want[i] {
+					t.Errorf("expected event %d to have count %d, got %d", i, test.want[i], got[i].Count)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildFiltersAroundAfterEmptyResult(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	normal := testEvent("ns", "a", "Scheduled", base)
+	normal.Type = corev1.EventTypeNormal
+
+	tests := []struct {
+		name   string
+		opts   FilterOptions
+		events []*corev1.Event
+	}{
+		{name: "no events", opts: FilterOptions{Around: "12:00", AroundDuration: time.Minute}, events: nil},
+		{name: "warnings only", opts: FilterOptions{Around: "12:00", AroundDuration: time.Minute, WarningOnly: true}, events: []*corev1.Event{normal}},
+		{name: "other namespace", opts: FilterOptions{Around: "12:00", AroundDuration: time.Minute, Namespaces: []string{"other"}}, events: []*corev1.Event{normal}},
+		{name: "several arounds", opts: FilterOptions{Arounds: []string{"12:00", "13:00/5m"}, WarningOnly: true}, events: []*corev1.Event{normal}},
+		{name: "nothing around", opts: FilterOptions{Around: "15:00", AroundDuration: time.Minute, Types: []string{"Normal"}}, events: []*corev1.Event{normal}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := BuildFilters(test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filters.FilterEvents(test.events...); len(got) != 0 {
+				t.Errorf("expected no events, got %v", got)
+			}
+		})
+	}
+}
+
+func TestBuildFiltersOrder(t *testing.T) {
+	opts := FilterOptions{
+		Around:             "12:00",
+		AroundDuration:     time.Minute,
+		Since:              "1h",
+		SinceTime:          "11:00",
+		MaxAge:             time.Hour,
+		UIDs:               []string{"uid"},
+		EventUIDs:          []string{"uid"},
+		ResourceVersions:   []string{"42"},
+		MinResourceVersion: 1,
+		Reasons:            []string{"BackOff"},
+		Actions:            []string{"Binding"},
+		Messages:           []string{"back-off"},
+		MinMessageLength:   1,
+		Names:              []string{"pod"},
+		NameRegexps:        []string{"^pod"},
+		Namespaces:         []string{"ns"},
+		ClusterScoped:      true,
+		Namespaced:         true,
+		NamespaceRegexps:   []string{"^ns"},
+		Kinds:              []string{"pods"},
+		APIVersions:        []string{"v1"},
+		RelatedKinds:       []string{"ReplicaSet"},
+		Components:         []string{"kubelet"},
+		Hosts:              []string{"node"},
+		ReportingInstances: []string{"node"},
+		FieldPaths:         []string{"spec.containers{app}"},
+		FieldSelector:      "reason=BackOff",
+		MinCount:           2,
+		MinObjects:         2,
+		MinSpan:            time.Minute,
+		MinRatePerMinute:   1,
+		Types:              []string{corev1.EventTypeWarning},
+		WarningOnly:        true,
+		HideBenign:         true,
+		Selector:           "app=web",
+		AnnotationSelector: "owner=team",
+		Dedup:              true,
+	}
+	expected := []string{
+		"*events.FilterByAround",
+		"*events.FilterBySince",
+		"*events.FilterByTimeRange",
+		"*events.FilterByAge",
+		"*events.FilterByUIDs",
+		"*events.FilterByEventUIDs",
+		"*events.FilterByResourceVersion",
+		"*events.FilterByResourceVersionRange",
+		"*events.FilterByReasons",
+		"*events.FilterByAction",
+		"*events.FilterByMessage",
+		"*events.FilterByMessageLength",
+		"*events.FilterByNames",
+		"*events.FilterByNameRegex",
+		"*events.FilterByNamespaces",
+		"*events.FilterByClusterScoped",
+		"*events.FilterByClusterScoped",
+		"*events.FilterByNamespaceRegex",
+		"*events.FilterByKind",
+		"*events.FilterByAPIVersion",
+		"*events.FilterByRelated",
+		"*events.FilterByComponent",
+		"*events.FilterByHosts",
+		"*events.FilterByReportingInstance",
+		"*events.FilterByFieldPath",
+		"*events.FilterByFieldSelector",
+		"*events.FilterByType",
+		"*events.FilterByType",
+		"*events.FilterByBenign",
+		"*events.FilterByInvolvedObjectLabels",
+		"*events.FilterByInvolvedObjectAnnotations",
+		"*events.FilterByDedup",
+		"*events.FilterByCount",
+		"*events.FilterByFlapping",
+		"*events.FilterByObjectSpread",
+		"*events.FilterByRate",
+	}
+
+	filters, err := BuildFilters(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, filter := range filters {
+		got = append(got, fmt.Sprintf("%T", filter))
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected filters\n%v\ngot\n%v", expected, got)
+	}
+}
+
+func TestBuildFiltersOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     FilterOptions
+		expected EventFilter
+	}{
+		{name: "no options"},
+		{name: "reasons", opts: FilterOptions{Reasons: []string{"BackOff"}, IgnoreCase: true, ReasonPrefix: true}, expected: NewFilterByReasons([]string{"BackOff"}, true, true)},
+		{name: "names", opts: FilterOptions{Names: []string{"a", "-b"}}, expected: &FilterByNames{Names: sets.NewString("a", "-b")}},
+		{name: "namespaces", opts: FilterOptions{Namespaces: []string{"ns"}}, expected: &FilterByNamespaces{Namespaces: sets.NewString("ns")}},
+		{name: "kinds", opts: FilterOptions{Kinds: []string{"pods", "-apps/deployments"}}, expected: &FilterByKind{Kinds: map[schema.GroupKind]bool{{Kind: "Pod"}: true, {Group: "apps", Kind: "-Deployment"}: true}}},
+		{name: "components", opts: FilterOptions{Components: []string{"kubelet"}}, expected: &FilterByComponent{Components: sets.NewString("kubelet")}},
+		{name: "types", opts: FilterOptions{Types: []string{"Normal"}}, expected: &FilterByType{Types: sets.NewString("Normal")}},
+		{name: "warning only", opts: FilterOptions{WarningOnly: true}, expected: &FilterByType{Types: sets.NewString(corev1.EventTypeWarning)}},
+		{name: "max age from the newest event", opts: FilterOptions{MaxAge: time.Hour}, expected: &FilterByAge{MaxAge: time.Hour, FromNewest: true}},
+		{name: "count", opts: FilterOptions{MinCount: 2, MaxCount: 5}, expected: &FilterByCount{MinCount: 2, MaxCount: 5}},
+		{name: "dedup", opts: FilterOptions{Dedup: true}, expected: &FilterByDedup{}},
+		{name: "since", opts: FilterOptions{Since: "2d"}, expected: &FilterBySince{Since: 48 * time.Hour}},
+		{name: "zero since", opts: FilterOptions{Since: "0s"}},
+		{name: "cluster-scoped", opts: FilterOptions{ClusterScoped: true}, expected: &FilterByClusterScoped{}},
+		{name: "namespaced", opts: FilterOptions{Namespaced: true}, expected: &FilterByClusterScoped{Namespaced: true}},
+		{name: "benign", opts: FilterOptions{HideBenign: true}, expected: NewFilterByBenign(nil)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := BuildFilters(test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected := EventFilters{}
+			if test.expected != nil {
+				expected = append(expected, test.expected)
+			}
+			if !reflect.DeepEqual(filters, expected) {
+				t.Errorf("expected %#v, got %#v", expected, filters)
+			}
+		})
+	}
+}
+
+func TestBuildFiltersErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts FilterOptions
+	}{
+		{name: "bad around", opts: FilterOptions{Around: "noon"}},
+		{name: "bad around duration", opts: FilterOptions{Arounds: []string{"12:00/-5m"}}},
+		{name: "bad around time zone", opts: FilterOptions{Around: "12:00", AroundTimeZone: "Nowhere/Nothing"}},
+		{name: "bad since", opts: FilterOptions{Since: "yesterday"}},
+		{name: "since after until", opts: FilterOptions{SinceTime: "13:00", Until: "12:00"}},
+		{name: "bad kind", opts: FilterOptions{Kinds: []string{"pdos"}}},
+		{name: "bad kind format", opts: FilterOptions{Kinds: []string{"a/b/c"}}},
+		{name: "bad message regexp", opts: FilterOptions{Messages: []string{"("}, MessageRegexp: true}},
+		{name: "bad api version", opts: FilterOptions{APIVersions: []string{"a/b/c"}}},
+		{name: "bad field selector", opts: FilterOptions{FieldSelector: "reason"}},
+		{name: "bad namespace regexp", opts: FilterOptions{NamespaceRegexps: []string{"["}}},
+		{name: "bad selector", opts: FilterOptions{Selector: "app in"}},
+		{name: "bad annotation selector", opts: FilterOptions{AnnotationSelector: "!="}},
+		{name: "bad name regexp", opts: FilterOptions{NameRegexps: []string{"["}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if filters, err := BuildFilters(test.opts); err == nil {
+				t.Errorf("expected an error, got %v", filters)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package events
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ClusterByTimeGaps splits the events into incidents, bursts of events separated by quiet periods longer than maxGap.
+// The events are ordered by EventTimestamp, a new incident starts whenever an event comes more than maxGap after the
+// previous one.  Events without any timestamp belong to no incident and are left out.  The events passed in are not
+// reordered.
+func ClusterByTimeGaps(events []*corev1.Event, maxGap time.Duration) [][]*corev1.Event {
+	sorted := make([]*corev1.Event, 0, len(events))
+	for _, event := range events {
+		if !EventTimestamp(event).IsZero() {
+			sorted = append(sorted, event)
+		}
+	}
+	SortEventsByTime(sorted, true)
+
+	incidents := [][]*corev1.Event{}
+	var previous time.Time
+	for _, event := range sorted {
+		t := EventTimestamp(event)
+		if len(incidents) == 0 || t.Sub(previous) > maxGap {
+			incidents = append(incidents, []*corev1.Event{})
+		}
+		incidents[len(incidents)-1] = append(incidents[len(incidents)-1], event)
+		previous = t
+	}
+	return incidents
+}
+
+// IncidentSummary describes an incident of ClusterByTimeGaps.
+type IncidentSummary struct {
+	// Start and End are the EventTimestamps of the first and last event of the incident
+	Start time.Time
+	End   time.Time
+	// Events is the number of events of the incident, Count their summed count
+	Events int
+	Count  int
+	// Warnings is the number of Warning events of the incident
+	Warnings int
+}
+
+// Span returns the time from the first to the last event of the incident.
+func (s IncidentSummary) Span() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// SummarizeIncidents summarizes each incident of ClusterByTimeGaps, in the same order.
+func SummarizeIncidents(incidents [][]*corev1.Event) []IncidentSummary {
+	ret := make([]IncidentSummary, 0, len(incidents))
+	for _, incident := range incidents {
+		summary := IncidentSummary{Events: len(incident)}
+		for _, event := range incident {
+			t := EventTimestamp(event)
+			if summary.Start.IsZero() || t.Before(summary.Start) {
+				summary.Start = t
+			}
+			if t.After(summary.End) {
+				summary.End = t
+			}
+			summary.Count += eventCount(event)
+			if event.Type == corev1.EventTypeWarning {
+				summary.Warnings++
+			}
+		}
+		ret = append(ret, summary)
+	}
+	return ret
+}
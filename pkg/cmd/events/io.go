@@ -1,12 +1,22 @@
 package events
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 func PrintComponents(writer io.Writer, events []*corev1.Event) error {
@@ -24,8 +34,38 @@ func PrintComponents(writer io.Writer, events []*corev1.Event) error {
 	return nil
 }
 
+// warningColor and resetColor are the ANSI escapes around Warning events printed in color
+const (
+	warningColor = "\x1b[31m"
+	resetColor   = "\x1b[0m"
+)
+
 func PrintEvents(writer io.Writer, events []*corev1.Event) error {
-	for _, event := range events {
+	return eventPrinter{}.print(writer, events)
+}
+
+// PrintEventsColor is PrintEvents with Warning events in red, for terminals.
+func PrintEventsColor(writer io.Writer, events []*corev1.Event) error {
+	return eventPrinter{color: true}.print(writer, events)
+}
+
+// PrintEventsDelta is PrintEvents with every line prefixed by the +MM:SS since the previous event, the events must be
+// sorted by time.  Long quiet periods before a burst of events stand out this way.
+func PrintEventsDelta(writer io.Writer, events []*corev1.Event) error {
+	return eventPrinter{delta: true}.print(writer, events)
+}
+
+// eventPrinter prints events in the default format.
+type eventPrinter struct {
+	// color prints Warning events in red
+	color bool
+	// delta prefixes every line with the time since the previous event
+	delta bool
+}
+
+func (p eventPrinter) print(writer io.Writer, events []*corev1.Event) error {
+	previous := time.Time{}
+	for i, event := range events {
 		message := event.Message
 		message = strings.Replace(message, "\\\\", "\\", -1)
 		message = strings.Replace(message, "\\n", "\n\t", -1)
@@ -48,7 +88,18 @@ func PrintEvents(writer io.Writer, events []*corev1.Event) error {
 			componentName = fmt.Sprintf("%s-%s", event.ReportingController, event.ReportingInstance)
 		}
 
-		if _, err := fmt.Fprintf(writer, "%s (%s) %q %s %s\n", event.LastTimestamp.Format("15:04:05"), countMessage, componentName, event.Reason, message); err != nil {
+		line := fmt.Sprintf("%s (%s) %q %s %s", event.LastTimestamp.Format("15:04:05"), countMessage, componentName, event.Reason, message)
+		if p.delta {
+			current := EventTimestamp(event)
+			line = formatDelta(i == 0, previous, current) + " " + line
+			if !current.IsZero() {
+				previous = current
+			}
+		}
+		if p.color && event.Type == corev1.EventTypeWarning {
+			line = warningColor + line + resetColor
+		}
+		if _, err := fmt.Fprintln(writer, line); err != nil {
 			return err
 		}
 	}
@@ -56,6 +107,418 @@ func PrintEvents(writer io.Writer, events []*corev1.Event) error {
 	return nil
 }
 
+// formatDelta returns the time from previous to current as +MM:SS, where minutes go past 59 rather than adding hours.
+// The first event is +00:00, and an event without a timestamp, or the first one after only such events, is +--:--.
+func formatDelta(first bool, previous, current time.Time) string {
+	if first {
+		return "+00:00"
+	}
+	if previous.IsZero() || current.IsZero() {
+		return "+--:--"
+	}
+	delta := current.Sub(previous)
+	if delta < 0 {
+		delta = 0
+	}
+	return fmt.Sprintf("+%02d:%02d", int(delta/time.Minute), int(delta%time.Minute/time.Second))
+}
+
 func PrintEventsWide(writer io.Writer, events []*corev1.Event) error {
-	return PrintEvents(writer, events)
+	return WriteEventsWide(writer, events)
+}
+
+// WriteEventsWide writes the events in the columns of kubectl get events -o wide, with LAST SEEN and FIRST SEEN as ages
+// relative to now, so the output reads the same whether it came from the cluster or from a must-gather.
+func WriteEventsWide(writer io.Writer, events []*corev1.Event) error {
+	return writeEventsWide(writer, events, true, time.Now())
+}
+
+// writeEventsWide writes the events of WriteEventsWide with ages relative to now, the header only when header is set.
+func writeEventsWide(writer io.Writer, events []*corev1.Event, header bool, now time.Time) error {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if header {
+		if _, err := fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tSUBOBJECT\tSOURCE\tMESSAGE\tFIRST SEEN\tCOUNT\tNAME"); err != nil {
+			return err
+		}
+	}
+	for _, event := range events {
+		// the same fallbacks as kubectl, for events of the events.k8s.io API that only populate EventTime and Series
+		firstSeen := event.FirstTimestamp.Time
+		if firstSeen.IsZero() {
+			firstSeen = event.EventTime.Time
+		}
+		lastSeen := event.LastTimestamp.Time
+		count := event.Count
+		if event.Series != nil {
+			lastSeen = event.Series.LastObservedTime.Time
+			count = event.Series.Count
+		} else if lastSeen.IsZero() {
+			lastSeen = firstSeen
+		}
+		if count == 0 {
+			count = 1
+		}
+
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			wideAge(lastSeen, now), event.Type, event.Reason,
+			strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, event.InvolvedObject.FieldPath,
+			wideSource(event), strings.TrimSpace(event.Message), wideAge(firstSeen, now), count, event.Name); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// wideAge returns the time from t to now like kubectl prints ages, or <unknown> without a timestamp.
+func wideAge(t, now time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return humanDuration(now.Sub(t))
+}
+
+// wideSource returns the SOURCE column of kubectl, the component and host of the event, or the reporting controller of
+// events that only set that.
+func wideSource(event *corev1.Event) string {
+	component := event.Source.Component
+	if len(component) == 0 {
+		component = event.ReportingController
+	}
+	if len(event.Source.Host) == 0 {
+		return component
+	}
+	return component + ", " + event.Source.Host
+}
+
+// WriteEvents writes the events as json, a JSON array, yaml, a v1 List, or list, a v1 EventList in JSON that can be
+// read back by anything that reads kubectl output.  Timestamps are written as RFC3339 in UTC.
+func WriteEvents(writer io.Writer, events []*corev1.Event, format string) error {
+	switch format {
+	case "json":
+		return PrintEventsJSON(writer, events)
+	case "yaml":
+		return PrintEventsYAML(writer, events)
+	case "list":
+		list := &corev1.EventList{
+			TypeMeta: metav1.TypeMeta{Kind: "EventList", APIVersion: "v1"},
+			Items:    make([]corev1.Event, 0, len(events)),
+		}
+		for _, event := range events {
+			list.Items = append(list.Items, *withTypeMeta(event))
+		}
+		printer := &printers.JSONPrinter{}
+		return printer.PrintObj(list, writer)
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of json, yaml or list", format)
+	}
+}
+
+// PrintEventsJSON writes the events as a single JSON array, formatted like `kubectl get events -o json`.
+func PrintEventsJSON(writer io.Writer, events []*corev1.Event) error {
+	if _, err := fmt.Fprint(writer, "["); err != nil {
+		return err
+	}
+	for i, event := range events {
+		separator := "\n    "
+		if i > 0 {
+			separator = ",\n    "
+		}
+		data, err := json.MarshalIndent(withTypeMeta(event), "    ", "    ")
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(writer, "%s%s", separator, data); err != nil {
+			return err
+		}
+	}
+	if len(events) > 0 {
+		if _, err := fmt.Fprint(writer, "\n"); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(writer, "]"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// PrintEventsYAML writes the events as a v1 List, formatted like `kubectl get events -o yaml`.
+func PrintEventsYAML(writer io.Writer, events []*corev1.Event) error {
+	list := &metav1.List{
+		TypeMeta: metav1.TypeMeta{Kind: "List", APIVersion: "v1"},
+		Items:    []runtime.RawExtension{},
+	}
+	for _, event := range events {
+		list.Items = append(list.Items, runtime.RawExtension{Object: withTypeMeta(event)})
+	}
+
+	printer := &printers.YAMLPrinter{}
+	return printer.PrintObj(list, writer)
+}
+
+// DefaultCSVColumns are the columns written by WriteEventsCSV and WriteEventsTSV when none are chosen.
+var DefaultCSVColumns = []string{"lastTimestamp", "namespace", "name", "kind", "reason", "count", "component", "message"}
+
+// csvColumns are the columns WriteEventsCSV and WriteEventsTSV can write.
+var csvColumns = map[string]func(event *corev1.Event) string{
+	// events.k8s.io events often only set EventTime and leave Count zero, they are read the way the filters read them
+	"lastTimestamp": func(event *corev1.Event) string {
+		t := EventTimestamp(event)
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	},
+	"count":     func(event *corev1.Event) string { return strconv.Itoa(eventCount(event)) },
+	"type":      func(event *corev1.Event) string { return event.Type },
+	"reason":    func(event *corev1.Event) string { return event.Reason },
+	"namespace": func(event *corev1.Event) string { return event.InvolvedObject.Namespace },
+	"kind":      func(event *corev1.Event) string { return event.InvolvedObject.Kind },
+	"name":      func(event *corev1.Event) string { return event.InvolvedObject.Name },
+	"component": eventComponent,
+	"message":   func(event *corev1.Event) string { return event.Message },
+}
+
+// WriteEventsCSV writes a header and one row per event with the chosen columns, quoted per RFC 4180.  The columns are
+// lastTimestamp, count, type, reason, namespace, kind, name, component and message, DefaultCSVColumns when empty.
+func WriteEventsCSV(writer io.Writer, events []*corev1.Event, columns []string) error {
+	return writeEventsDelimited(writer, events, columns, ',')
+}
+
+// WriteEventsTSV is WriteEventsCSV separated by tabs.
+func WriteEventsTSV(writer io.Writer, events []*corev1.Event, columns []string) error {
+	return writeEventsDelimited(writer, events, columns, '\t')
+}
+
+func writeEventsDelimited(writer io.Writer, events []*corev1.Event, columns []string, comma rune) error {
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if _, ok := csvColumns[column]; !ok {
+			return fmt.Errorf("unsupported column %q, must be one of lastTimestamp, count, type, reason, namespace, kind, name, component or message", column)
+		}
+		header = append(header, strings.ToUpper(column[:1])+column[1:])
+	}
+
+	w := csv.NewWriter(writer)
+	w.Comma = comma
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, event := range events {
+		row := make([]string, 0, len(columns))
+		for _, column := range columns {
+			row = append(row, csvColumns[column](event))
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// customColumn is a column of -o custom-columns, a heading and the JSONPath of the value under it.
+type customColumn struct {
+	heading string
+	path    *jsonpath.JSONPath
+}
+
+// parseCustomColumns parses a custom-columns spec like LAST:.lastTimestamp,REASON:.reason, as kubectl does.
+func parseCustomColumns(spec string) ([]customColumn, error) {
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+	columns := []customColumn{}
+	for _, column := range strings.Split(spec, ",") {
+		parts := strings.SplitN(column, ":", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			return nil, fmt.Errorf("unexpected custom-columns spec %q, expected <header>:<json-path-expr>", column)
+		}
+		expression := parts[1]
+		if !strings.HasPrefix(expression, "{") {
+			if !strings.HasPrefix(expression, ".") {
+				expression = "." + expression
+			}
+			expression = "{" + expression + "}"
+		}
+		path := jsonpath.New(parts[0]).AllowMissingKeys(true)
+		if err := path.Parse(expression); err != nil {
+			return nil, fmt.Errorf("invalid custom-columns expression %q: %v", parts[1], err)
+		}
+		columns = append(columns, customColumn{heading: parts[0], path: path})
+	}
+	return columns, nil
+}
+
+// PrintEventsCustomColumns writes the events as an aligned table of the columns of a custom-columns spec, like
+// LAST:.lastTimestamp,NS:.involvedObject.namespace,REASON:.reason.  Fields an event does not have are written as
+// <none>.
+func PrintEventsCustomColumns(writer io.Writer, events []*corev1.Event, spec string) error {
+	columns, err := parseCustomColumns(spec)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	headings := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headings = append(headings, column.heading)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(headings, "\t")); err != nil {
+		return err
+	}
+	for _, event := range events {
+		// the JSONPaths use the JSON field names, as in the output of kubectl get events -o json
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(event)
+		if err != nil {
+			return err
+		}
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, customColumnValue(column.path, obj))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func customColumnValue(path *jsonpath.JSONPath, obj interface{}) string {
+	results, err := path.FindResults(obj)
+	if err != nil {
+		return "<none>"
+	}
+	values := []string{}
+	for _, result := range results {
+		for _, value := range result {
+			if !value.IsValid() || (value.Kind() == reflect.Interface && value.IsNil()) {
+				continue
+			}
+			values = append(values, fmt.Sprint(value.Interface()))
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
+
+// PrintSummary writes one row per summary entry, the heading names the column the entries are grouped by.
+func PrintSummary(writer io.Writer, heading string, entries []SummaryEntry) error {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintf(w, "%s\tCOUNT\n", strings.ToUpper(heading)); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		key := entry.Key
+		if len(key) == 0 {
+			key = "<none>"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%d\n", key, entry.Total); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// PrintDisplayRows writes the rows of CollapseForDisplay in columns, like kubectl get events.
+func PrintDisplayRows(writer io.Writer, rows []DisplayRow) error {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "LAST SEEN\tFIRST SEEN\tCOUNT\tTYPE\tNAMESPACE\tOBJECT\tREASON\tMESSAGE"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		namespace := row.InvolvedObject.Namespace
+		if len(namespace) == 0 {
+			namespace = "<none>"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s/%s\t%s\t%s\n",
+			row.LastSeen.Format("15:04:05"), row.FirstSeen.Format("15:04:05"), row.Count, row.Type, namespace,
+			strings.ToLower(row.InvolvedObject.Kind), row.InvolvedObject.Name, row.Reason, row.Message); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// PrintIncidents writes one row per incident, with the top reasons of its events to tell the incidents apart.
+func PrintIncidents(writer io.Writer, incidents [][]*corev1.Event) error {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "START\tEND\tSPAN\tEVENTS\tCOUNT\tWARNINGS\tTOP REASONS"); err != nil {
+		return err
+	}
+	for i, summary := range SummarizeIncidents(incidents) {
+		reasons := []string{}
+		for _, entry := range SortSummary(SummarizeByReason(incidents[i])) {
+			if len(reasons) == 3 {
+				break
+			}
+			reasons = append(reasons, fmt.Sprintf("%s(%d)", entry.Key, entry.Total))
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			summary.Start.Format("15:04:05"), summary.End.Format("15:04:05"), summary.Span(), summary.Events, summary.Count,
+			summary.Warnings, strings.Join(reasons, ",")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// PrintObjects writes one row per involved object, as kind/namespace/name, with the total count of its events.
+func PrintObjects(writer io.Writer, objects []ObjectEventCount) error {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "OBJECT\tUID\tCOUNT"); err != nil {
+		return err
+	}
+	for _, object := range objects {
+		uid := string(object.Object.UID)
+		if len(uid) == 0 {
+			uid = "<none>"
+		}
+		if _, err := fmt.Fprintf(w, "%s/%s/%s\t%s\t%d\n",
+			strings.ToLower(object.Object.Kind), object.Object.Namespace, object.Object.Name, uid, object.Total); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// PrintSeries writes one row per series of events.
+func PrintSeries(writer io.Writer, series []EventSeriesSummary) error {
+	w := tabwriter.NewWriter(writer, 0, 8, 2, ' ', 0)
+	if _, err := fmt.Fprintln(w, "FIRST SEEN\tLAST SEEN\tCOUNT\tNAMESPACE\tOBJECT\tREASON\tCONTROLLERS\tMESSAGE"); err != nil {
+		return err
+	}
+	for _, curr := range series {
+		namespace := curr.InvolvedObject.Namespace
+		if len(namespace) == 0 {
+			namespace = "<none>"
+		}
+		controllers := strings.Join(curr.ReportingControllers, ",")
+		if len(controllers) == 0 {
+			controllers = "<none>"
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s/%s\t%s\t%s\t%s\n",
+			curr.FirstObserved.Format("15:04:05"), curr.LastObserved.Format("15:04:05"), curr.Count, namespace,
+			strings.ToLower(curr.InvolvedObject.Kind), curr.InvolvedObject.Name, curr.Reason, controllers, curr.Message); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// withTypeMeta returns the event with its kind and apiVersion set, which decoding typically clears.
+func withTypeMeta(event *corev1.Event) *corev1.Event {
+	if len(event.Kind) > 0 && len(event.APIVersion) > 0 {
+		return event
+	}
+	event = event.DeepCopy()
+	event.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Event"))
+	return event
 }
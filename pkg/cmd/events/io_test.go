@@ -0,0 +1,97 @@
+package events
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the tests")
+
+// wideEvents returns events exercising every column and fallback of the wide output, relative to now.
+func wideEvents(now time.Time) []*corev1.Event {
+	backOff := testEvent("ns", "web-7d8f9c6b5-x2x9k", "BackOff", now.Add(-time.Hour))
+	backOff.Count = 12
+	backOff.LastTimestamp = metav1.NewTime(now.Add(-30 * time.Second))
+	backOff.InvolvedObject.FieldPath = "spec.containers{app}"
+	backOff.Message = "Back-off restarting failed container\n"
+	backOff.Source = corev1.EventSource{Component: "kubelet", Host: "worker-0"}
+
+	scheduled := testEvent("ns", "db-0", "Scheduled", now.Add(-3*24*time.Hour))
+	scheduled.Type = corev1.EventTypeNormal
+	scheduled.Message = "Successfully assigned ns/db-0 to worker-1"
+	scheduled.Source = corev1.EventSource{Component: "default-scheduler"}
+
+	// an event of the events.k8s.io API, with EventTime and a Series instead of timestamps and a count
+	series := testEvent("", "master-0", "NodeNotReady", time.Time{})
+	series.InvolvedObject.Kind = "Node"
+	series.FirstTimestamp, series.LastTimestamp, series.Count = metav1.Time{}, metav1.Time{}, 0
+	series.EventTime = metav1.NewMicroTime(now.Add(-2 * time.Hour))
+	series.Series = &corev1.EventSeries{Count: 4, LastObservedTime: metav1.NewMicroTime(now.Add(-5 * time.Minute))}
+	series.ReportingController = "node-controller"
+
+	undated := testEvent("openshift-etcd", "etcd-operator", "OperatorStatusChanged", time.Time{})
+	undated.InvolvedObject.Kind = "Deployment"
+	undated.FirstTimestamp, undated.LastTimestamp, undated.Count = metav1.Time{}, metav1.Time{}, 0
+
+	return []*corev1.Event{backOff, scheduled, series, undated}
+}
+
+func TestWriteEventsWide(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		golden string
+		header bool
+	}{
+		{golden: "wide.golden", header: true},
+		{golden: "wide-no-header.golden", header: false},
+	}
+	for _, test := range tests {
+		t.Run(test.golden, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			if err := writeEventsWide(out, wideEvents(now), test.header, now); err != nil {
+				t.Fatal(err)
+			}
+
+			golden := filepath.Join("testdata", test.golden)
+			if *update {
+				if err := ioutil.WriteFile(golden, out.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out.Bytes(), expected) {
+				t.Errorf("expected the output of %s:\n%s\ngot:\n%s", golden, expected, out.String())
+			}
+		})
+	}
+}
+
+func TestWriteEventsCSV(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	out := &bytes.Buffer{}
+	if err := WriteEventsCSV(out, wideEvents(now), []string{"name", "lastTimestamp", "count"}); err != nil {
+		t.Fatal(err)
+	}
+	// the events.k8s.io event is read from its EventTime, the events without a count as observed once
+	expected := `Name,LastTimestamp,Count
+web-7d8f9c6b5-x2x9k,2020-01-01T11:59:30Z,12
+db-0,2019-12-29T12:00:00Z,1
+master-0,2020-01-01T10:00:00Z,1
+etcd-operator,,1
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
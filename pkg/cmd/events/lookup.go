@@ -0,0 +1,68 @@
+package events
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// NewInvolvedObjectLookup returns an InvolvedObjectLookup that fetches the involved objects from the cluster.  Every
+// object is fetched once, by UID, whether it was found or not.  An object that has since been deleted, or replaced by
+// another object of the same name, is reported as NotFound so the filters skip its events, while other errors, like
+// Forbidden, fail the filters.
+func NewInvolvedObjectLookup(restClientGetter genericclioptions.RESTClientGetter) InvolvedObjectLookup {
+	type lookupResult struct {
+		obj metav1.Object
+		err error
+	}
+	cache := map[string]lookupResult{}
+
+	return func(ref corev1.ObjectReference) (metav1.Object, error) {
+		key := string(ref.UID)
+		if len(key) == 0 {
+			key = fmt.Sprintf("%s/%s/%s/%s", ref.APIVersion, ref.Kind, ref.Namespace, ref.Name)
+		}
+		if result, ok := cache[key]; ok {
+			return result.obj, result.err
+		}
+		obj, err := lookupInvolvedObject(restClientGetter, ref)
+		cache[key] = lookupResult{obj: obj, err: err}
+		return obj, err
+	}
+}
+
+func lookupInvolvedObject(restClientGetter genericclioptions.RESTClientGetter, ref corev1.ObjectReference) (metav1.Object, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, err
+	}
+	// Kind.version.group is resolved as a kind even for the core group, where the group is empty
+	kind := fmt.Sprintf("%s.%s.%s", ref.Kind, gv.Version, gv.Group)
+
+	info, err := resource.NewBuilder(restClientGetter).
+		Unstructured().
+		NamespaceParam(ref.Namespace).
+		ResourceNames(kind, ref.Name).
+		Do().Infos()
+	if err != nil {
+		return nil, err
+	}
+	if len(info) != 1 {
+		return nil, fmt.Errorf("expected one %s %s/%s, got %d", ref.Kind, ref.Namespace, ref.Name, len(info))
+	}
+	obj, err := meta.Accessor(info[0].Object)
+	if err != nil {
+		return nil, err
+	}
+	if len(ref.UID) > 0 && obj.GetUID() != ref.UID {
+		// the object the events are about was deleted, the one of the same name is another
+		return nil, errors.NewNotFound(schema.GroupResource{Group: gv.Group, Resource: ref.Kind}, ref.Name)
+	}
+	return obj, nil
+}
@@ -0,0 +1,44 @@
+package events
+
+import (
+	"regexp"
+)
+
+// MessageRule replaces every match of Pattern in a message by Replacement, which may refer to submatches like
+// regexp.ReplaceAllString.
+type MessageRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultMessageRules replace the tokens that differ between otherwise identical messages, in order: timestamps,
+// UUIDs, IP addresses with their port, durations like the 5m0s of a back-off, quoted names, generated name suffixes
+// like the -7d8f9 of a pod, numbers and hashes like image digests.  They can be replaced to change how messages are
+// grouped.
+var DefaultMessageRules = []MessageRule{
+	{Pattern: regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`), Replacement: "<time>"},
+	{Pattern: regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), Replacement: "<uid>"},
+	{Pattern: regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), Replacement: "<ip>"},
+	{Pattern: regexp.MustCompile(`\[[0-9a-fA-F:]*:[0-9a-fA-F:]*\](:\d+)?`), Replacement: "<ip>"},
+	{Pattern: regexp.MustCompile(`\b(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+\b`), Replacement: "<duration>"},
+	{Pattern: regexp.MustCompile(`"[^"]*"`), Replacement: `"<name>"`},
+	{Pattern: regexp.MustCompile(`'[^']*'`), Replacement: `'<name>'`},
+	{Pattern: regexp.MustCompile(`-[a-z0-9]*[0-9][a-z0-9]*\b`), Replacement: "-<id>"},
+	{Pattern: regexp.MustCompile(`\b\d+(\.\d+)?\b`), Replacement: "<n>"},
+	// after the numbers, so only the hex strings with a letter are left
+	{Pattern: regexp.MustCompile(`\b(sha256:)?[0-9a-f]{7,}\b`), Replacement: "<hash>"},
+}
+
+// NormalizeMessage applies the DefaultMessageRules to the message, so "Failed to pull image x-abc123" and "Failed to
+// pull image x-def456" both become "Failed to pull image x-<id>" and group together.
+func NormalizeMessage(message string) string {
+	return NormalizeMessageWith(DefaultMessageRules, message)
+}
+
+// NormalizeMessageWith applies the rules to the message in order.
+func NormalizeMessageWith(rules []MessageRule, message string) string {
+	for _, rule := range rules {
+		message = rule.Pattern.ReplaceAllString(message, rule.Replacement)
+	}
+	return message
+}
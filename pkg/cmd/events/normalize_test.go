@@ -0,0 +1,60 @@
+package events
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNormalizeMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{name: "uuid", message: "Deleted pod 6f1c3c2e-4b8a-4f3e-9a8e-2c5d7b9e1a0f", expected: "Deleted pod <uid>"},
+		{name: "upper case uuid", message: "Deleted pod 6F1C3C2E-4B8A-4F3E-9A8E-2C5D7B9E1A0F", expected: "Deleted pod <uid>"},
+		{name: "ipv4", message: "Liveness probe failed: Get http://10.128.2.15/healthz", expected: "Liveness probe failed: Get http://<ip>/healthz"},
+		{name: "ipv4 with port", message: "dial tcp 10.0.0.1:6443: connect: connection refused", expected: "dial tcp <ip>: connect: connection refused"},
+		{name: "ipv6 with port", message: "dial tcp [fd00::1]:2379: i/o timeout", expected: "dial tcp <ip>: i/o timeout"},
+		{name: "numbers", message: "0/6 nodes are available: 3 Insufficient cpu, 3 node(s) had taints", expected: "<n>/<n> nodes are available: <n> Insufficient cpu, <n> node(s) had taints"},
+		{name: "decimal", message: "Memory usage at 93.5 percent", expected: "Memory usage at <n> percent"},
+		{name: "duration", message: "Back-off 5m0s restarting failed container", expected: "Back-off <duration> restarting failed container"},
+		{name: "fractional durations", message: "Probe took 1.5s, timeout is 250ms", expected: "Probe took <duration>, timeout is <duration>"},
+		{name: "compound duration", message: "Lease expired 1h2m3.5s ago", expected: "Lease expired <duration> ago"},
+		{name: "hash", message: "Container image hash 3f2a9b1c changed", expected: "Container image hash <hash> changed"},
+		{name: "digest", message: "Pulled image quay.io/app@sha256:9b2c4e6a8d0f1e3b5a7c9d1f3e5b7a9c0d2e4f6a8b0c1d3e5f7a9b1c3d5e7f9a", expected: "Pulled image quay.io/app@<hash>"},
+		{name: "timestamp", message: "Last heartbeat at 2020-01-01T12:00:00Z", expected: "Last heartbeat at <time>"},
+		{name: "timestamp with offset and fraction", message: "Started at 2020-01-01 12:00:00.123456+02:00", expected: "Started at <time>"},
+		{name: "double quoted name", message: `Error: secret "db-password" not found`, expected: `Error: secret "<name>" not found`},
+		{name: "single quoted name", message: "Error: configmap 'app-config' not found", expected: "Error: configmap '<name>' not found"},
+		{name: "generated suffix", message: "Created pod: web-7d8f9c6b5-x2x9k", expected: "Created pod: web-<id>-<id>"},
+		{name: "suffix without digits", message: "Scaled up replica set web-api", expected: "Scaled up replica set web-api"},
+		{name: "words", message: "Successfully assigned the pod, accepted by the scheduler", expected: "Successfully assigned the pod, accepted by the scheduler"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NormalizeMessage(test.message); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeMessageGroups(t *testing.T) {
+	if a, b := NormalizeMessage("Failed to pull image x-abc123"), NormalizeMessage("Failed to pull image x-def456"); a != b {
+		t.Errorf("expected the messages to normalize alike, got %q and %q", a, b)
+	}
+}
+
+func TestNormalizeMessageWith(t *testing.T) {
+	rules := []MessageRule{
+		{Pattern: regexp.MustCompile(`node/(\S+)`), Replacement: "node/<node>"},
+		{Pattern: regexp.MustCompile(`(\d+) times`), Replacement: "N times"},
+	}
+	if got, expected := NormalizeMessageWith(rules, "node/master-0 rebooted 3 times"), "node/<node> rebooted N times"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+	if got, expected := NormalizeMessageWith(nil, "node/master-0 rebooted 3 times"), "node/master-0 rebooted 3 times"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressInterval is how often progress is reported.
+const progressInterval = 500 * time.Millisecond
+
+// progress reports how far a long run got, at most every progressInterval.  Runs that finish before the first report
+// print nothing.  On a terminal every report overwrites the previous one, otherwise each is a line of its own.  A nil
+// progress reports nothing.
+type progress struct {
+	out      io.Writer
+	terminal bool
+
+	start time.Time
+	last  time.Time
+	// reported is set once anything was written
+	reported bool
+}
+
+func newProgress(out io.Writer, terminal bool) *progress {
+	now := time.Now()
+	return &progress{out: out, terminal: terminal, start: now, last: now}
+}
+
+// report writes the message when progressInterval passed since the last report.
+func (p *progress) report(format string, args ...interface{}) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	if now.Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = now
+	p.write(format, args...)
+}
+
+// done writes the final message when anything was reported before, ending the line on a terminal.
+func (p *progress) done(format string, args ...interface{}) {
+	if p == nil || !p.reported {
+		return
+	}
+	p.write(format, args...)
+	if p.terminal {
+		fmt.Fprintln(p.out)
+	}
+}
+
+func (p *progress) write(format string, args ...interface{}) {
+	p.reported = true
+	message := fmt.Sprintf(format, args...)
+	if p.terminal {
+		// clear the rest of the previous, possibly longer, message
+		fmt.Fprintf(p.out, "\r%s\x1b[K", message)
+		return
+	}
+	fmt.Fprintln(p.out, message)
+}
@@ -0,0 +1,285 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	eventsv1beta1 "k8s.io/api/events/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// errUnrecognizedEvents is returned for input that is neither a JSON or YAML List of events nor a stream of events.
+var errUnrecognizedEvents = fmt.Errorf("expected a JSON or YAML List of events, or newline-delimited JSON events")
+
+// LoadEventsFromFile reads the events in the file, or from stdin when the filename is "-".  Gzip compressed input is
+// decompressed as it is read, whatever the name of the file.
+func LoadEventsFromFile(filename string, stdin io.Reader) ([]*corev1.Event, error) {
+	return loadEventsFromFile(filename, stdin, nil)
+}
+
+// readProgress is told the number of events and files read so far, it may be nil.
+type readProgress func(events, files int)
+
+func loadEventsFromFile(filename string, stdin io.Reader, progress readProgress) ([]*corev1.Event, error) {
+	if filename == "-" {
+		events, err := readMaybeGzippedEvents(stdin, progress)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read events from stdin: %v", err)
+		}
+		return events, nil
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events, err := readMaybeGzippedEvents(file, progress)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read events from %s: %v", filename, err)
+	}
+	return events, nil
+}
+
+// gzipMagic are the first bytes of gzip compressed data
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// readMaybeGzippedEvents is ReadEvents on r, decompressing it first when it starts like gzip data.  Both are streamed so
+// large dumps are never held in memory as a whole.
+func readMaybeGzippedEvents(r io.Reader, progress readProgress) ([]*corev1.Event, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(len(gzipMagic)); err != nil || !bytes.Equal(magic, gzipMagic) {
+		return readEvents(buffered, progress)
+	}
+
+	decompressed, err := gzip.NewReader(buffered)
+	if err != nil {
+		return nil, err
+	}
+	defer decompressed.Close()
+	return readEvents(decompressed, progress)
+}
+
+// ReadEvents decodes a JSON or YAML List of events, a stream of JSON or YAML documents holding events or lists of
+// events, or newline-delimited JSON events.  The format is detected from the content.  Events of the events.k8s.io API
+// are converted to core v1 events.  Errors name the document of the stream they were found in, counting from 1.
+func ReadEvents(r io.Reader) ([]*corev1.Event, error) {
+	return readEvents(r, nil)
+}
+
+func readEvents(r io.Reader, progress readProgress) ([]*corev1.Event, error) {
+	events := []*corev1.Event{}
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for document := 1; ; document++ {
+		raw := json.RawMessage{}
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("document %d: %v: %v", document, errUnrecognizedEvents, err)
+		}
+		// empty YAML documents decode to null
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		decoded, err := decodeEvents(raw)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", document, err)
+		}
+		events = append(events, decoded...)
+		if progress != nil {
+			progress(len(events), 1)
+		}
+	}
+
+	return events, nil
+}
+
+// decodeEvents decodes a single JSON document holding an event or a list of events.
+func decodeEvents(raw []byte) ([]*corev1.Event, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, fmt.Errorf("%v: %v", errUnrecognizedEvents, err)
+	}
+
+	switch {
+	case typeMeta.Kind == "List" || typeMeta.Kind == "EventList":
+		list := metav1.List{}
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, err
+		}
+		events := []*corev1.Event{}
+		for _, item := range list.Items {
+			decoded, err := decodeEvents(item.Raw)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, decoded...)
+		}
+		return events, nil
+
+	case typeMeta.Kind == "Event" && (typeMeta.APIVersion == "v1" || len(typeMeta.APIVersion) == 0),
+		len(typeMeta.Kind) == 0 && len(typeMeta.APIVersion) == 0:
+		event := &corev1.Event{}
+		if err := json.Unmarshal(raw, event); err != nil {
+			return nil, err
+		}
+		// without a kind only something that looks like an event is accepted
+		if len(typeMeta.Kind) == 0 && len(event.InvolvedObject.Kind) == 0 && len(event.Reason) == 0 {
+			return nil, errUnrecognizedEvents
+		}
+		return []*corev1.Event{event}, nil
+
+	case typeMeta.Kind == "Event" && (typeMeta.APIVersion == "events.k8s.io/v1" || typeMeta.APIVersion == "events.k8s.io/v1beta1"):
+		// both versions share the fields that matter here, so v1 is read as v1beta1
+		event := &eventsv1beta1.Event{}
+		if err := json.Unmarshal(raw, event); err != nil {
+			return nil, err
+		}
+		return []*corev1.Event{eventFromEventsAPI(event)}, nil
+
+	default:
+		return nil, fmt.Errorf("unhandled resource: %s, %s", typeMeta.APIVersion, typeMeta.Kind)
+	}
+}
+
+// eventFromEventsAPI converts an events.k8s.io event to a core v1 event, the note becomes the message, regarding the
+// involved object and the deprecated fields their core counterparts.
+func eventFromEventsAPI(in *eventsv1beta1.Event) *corev1.Event {
+	out := &corev1.Event{
+		TypeMeta:            metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+		ObjectMeta:          in.ObjectMeta,
+		InvolvedObject:      in.Regarding,
+		Related:             in.Related,
+		Reason:              in.Reason,
+		Message:             in.Note,
+		Type:                in.Type,
+		Action:              in.Action,
+		EventTime:           in.EventTime,
+		ReportingController: in.ReportingController,
+		ReportingInstance:   in.ReportingInstance,
+		Source:              in.DeprecatedSource,
+		FirstTimestamp:      in.DeprecatedFirstTimestamp,
+		LastTimestamp:       in.DeprecatedLastTimestamp,
+		Count:               in.DeprecatedCount,
+	}
+	if in.Series != nil {
+		out.Series = &corev1.EventSeries{Count: in.Series.Count, LastObservedTime: in.Series.LastObservedTime}
+	}
+	return out
+}
+
+// LoadEventsFromMustGather reads the events stored in the YAML and JSON files under a must-gather directory, like
+// namespaces/<namespace>/core/events.yaml, also when they are gzip compressed as events.yaml.gz.  Files that do not
+// decode as events are skipped.  It returns the events and the number of files they were read from.
+func LoadEventsFromMustGather(dir string) ([]*corev1.Event, int, error) {
+	return loadEventsFromMustGather(dir, nil)
+}
+
+func loadEventsFromMustGather(dir string, progress readProgress) ([]*corev1.Event, int, error) {
+	events := []*corev1.Event{}
+	files := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(strings.TrimSuffix(path, ".gz")) {
+		case ".yaml", ".yml", ".json":
+		default:
+			return nil
+		}
+
+		decoded, err := LoadEventsFromFile(path, nil)
+		if err != nil || len(decoded) == 0 {
+			return nil
+		}
+		events = append(events, decoded...)
+		files++
+		if progress != nil {
+			progress(len(events), files)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, files, nil
+}
+
+// LoadEventsFromEtcdDump reads the events stored in a directory of etcd values, for clusters that are down and only left
+// a backup.  Every file holds the raw value of one key, as printed by etcdctl get --print-value-only, at the path of the
+// key under the directory.  The events of Kubernetes are stored under <prefix>/events/<namespace>/<name>, where the
+// prefix is /registry by default and /kubernetes.io on OpenShift, e.g. <dir>/kubernetes.io/events/default/web.15f0.
+// Files outside an events directory, or holding something other than a core event, are skipped.  Values are decoded
+// from protobuf, or from JSON for clusters storing JSON.  It returns the events and the number of files read.
+//
+// etcd snapshot files are not read directly, the keys have to be dumped from an etcd restored from the snapshot.
+func LoadEventsFromEtcdDump(dir string) ([]*corev1.Event, int, error) {
+	return loadEventsFromEtcdDump(dir, nil)
+}
+
+func loadEventsFromEtcdDump(dir string, progress readProgress) ([]*corev1.Event, int, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, 0, err
+	}
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	events := []*corev1.Event{}
+	files := 0
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		// the key is <prefix>/events/<namespace>/<name>
+		parts := strings.Split(filepath.ToSlash(path), "/")
+		if len(parts) < 3 || parts[len(parts)-3] != "events" {
+			return nil
+		}
+
+		value, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		obj, _, err := decoder.Decode(value, nil, nil)
+		if err != nil {
+			return nil
+		}
+		event, ok := obj.(*corev1.Event)
+		if !ok {
+			return nil
+		}
+		events = append(events, event)
+		files++
+		if progress != nil {
+			progress(len(events), files)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return events, files, nil
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// EventSeriesSummary is a series of events about the same object with the same reason and message, reconstructed from
+// the individual events of clusters that do not populate the Series of the events.k8s.io API.
+type EventSeriesSummary struct {
+	InvolvedObject corev1.ObjectReference
+	Reason         string
+	// Message is the message of the events as normalized by NormalizeMessage
+	Message string
+	// Count is the summed count of the events of the series
+	Count         int
+	FirstObserved time.Time
+	LastObserved  time.Time
+	// ReportingControllers are the components that reported the events of the series, sorted
+	ReportingControllers []string
+}
+
+// AggregateSeries groups the events into series by involved object, reason and normalized message, ordered by when they
+// were last observed, then by involved object.
+func AggregateSeries(events []*corev1.Event) []EventSeriesSummary {
+	type seriesKey struct {
+		object  string
+		uid     types.UID
+		reason  string
+		message string
+	}
+
+	series := map[seriesKey]*EventSeriesSummary{}
+	controllers := map[seriesKey]sets.String{}
+	for _, event := range events {
+		key := seriesKey{
+			object:  involvedObjectKey(event.InvolvedObject),
+			uid:     event.InvolvedObject.UID,
+			reason:  event.Reason,
+			message: NormalizeMessage(event.Message),
+		}
+		curr, ok := series[key]
+		if !ok {
+			curr = &EventSeriesSummary{InvolvedObject: event.InvolvedObject, Reason: event.Reason, Message: key.message}
+			series[key] = curr
+			controllers[key] = sets.NewString()
+		}
+
+		curr.Count += eventCount(event)
+		if first := firstObserved(event); !first.IsZero() && (curr.FirstObserved.IsZero() || first.Before(curr.FirstObserved)) {
+			curr.FirstObserved = first
+		}
+		if last := lastObserved(event); last.After(curr.LastObserved) {
+			curr.LastObserved = last
+		}
+		if component := eventComponent(event); len(component) > 0 {
+			controllers[key].Insert(component)
+		}
+	}
+
+	ret := make([]EventSeriesSummary, 0, len(series))
+	for key, curr := range series {
+		curr.ReportingControllers = controllers[key].List()
+		ret = append(ret, *curr)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].LastObserved.Equal(ret[j].LastObserved) {
+			return ret[i].LastObserved.Before(ret[j].LastObserved)
+		}
+		if keyI, keyJ := involvedObjectKey(ret[i].InvolvedObject), involvedObjectKey(ret[j].InvolvedObject); keyI != keyJ {
+			return keyI < keyJ
+		}
+		if ret[i].Reason != ret[j].Reason {
+			return ret[i].Reason < ret[j].Reason
+		}
+		return ret[i].Message < ret[j].Message
+	})
+	return ret
+}
+
+// firstObserved returns the FirstTimestamp of the event, or its EventTimestamp when it is not set.
+func firstObserved(event *corev1.Event) time.Time {
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp.Time
+	}
+	return EventTimestamp(event)
+}
+
+// lastObserved returns the last observed time of the Series of the event, or its LastTimestamp, or its EventTimestamp.
+func lastObserved(event *corev1.Event) time.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return EventTimestamp(event)
+}
+
+// DisplayRow is one line of the human output, standing for every event about the same object with the same type,
+// reason and message.
+type DisplayRow struct {
+	Type           string
+	InvolvedObject corev1.ObjectReference
+	Reason         string
+	Message        string
+	// Count is the summed count of the events of the row
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// CollapseForDisplay merges the events that only differ in when and how often they were seen into rows, so a storm of
+// repeated events prints as one line, like kubectl get events.  The rows keep the order of the first event of each, so
+// sorted events give sorted rows.  It is meant for printing only, the events themselves are not modified.
+func CollapseForDisplay(events []*corev1.Event) []DisplayRow {
+	type rowKey struct {
+		object    string
+		uid       types.UID
+		eventType string
+		reason    string
+		message   string
+	}
+
+	ret := []DisplayRow{}
+	index := map[rowKey]int{}
+	for _, event := range events {
+		key := rowKey{
+			object:    involvedObjectKey(event.InvolvedObject),
+			uid:       event.InvolvedObject.UID,
+			eventType: event.Type,
+			reason:    event.Reason,
+			message:   event.Message,
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(ret)
+			index[key] = i
+			ret = append(ret, DisplayRow{Type: event.Type, InvolvedObject: event.InvolvedObject, Reason: event.Reason, Message: event.Message})
+		}
+
+		row := &ret[i]
+		row.Count += eventCount(event)
+		if first := firstObserved(event); !first.IsZero() && (row.FirstSeen.IsZero() || first.Before(row.FirstSeen)) {
+			row.FirstSeen = first
+		}
+		if last := lastObserved(event); last.After(row.LastSeen) {
+			row.LastSeen = last
+		}
+	}
+	return ret
+}
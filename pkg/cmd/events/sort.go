@@ -1,6 +1,60 @@
 package events
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SortEvents orders the events by time (the default), lastTimestamp, firstTimestamp, count, rate (occurrences per
+// second over the span of the event) or reason, breaking ties on the involved object namespace and name so the output
+// is reproducible.
+func SortEvents(events []*corev1.Event, by string) error {
+	var primary sort.Interface
+	switch by {
+	case "", "time":
+		SortEventsByTime(events, true)
+		return nil
+	case "lastTimestamp":
+		primary = byTime(events)
+	case "firstTimestamp":
+		primary = byFirstTime(events)
+	case "count":
+		primary = byFrequency(events)
+	case "rate":
+		primary = byRate(events)
+	case "reason":
+		primary = byReason(events)
+	default:
+		return fmt.Errorf("unsupported sort field %q, must be one of time, lastTimestamp, firstTimestamp, count, rate or reason", by)
+	}
+
+	sort.Stable(withTieBreak{Interface: primary, tieBreak: byInvolvedObject(events)})
+	return nil
+}
+
+// SortEventsByTime orders the events by the best timestamp they have, preferring EventTime, then LastTimestamp, then
+// FirstTimestamp, breaking ties on the involved object namespace and name.  Events without any timestamp sort last.
+func SortEventsByTime(events []*corev1.Event, ascending bool) {
+	sort.Stable(withTieBreak{Interface: byBestTime{events: events, ascending: ascending}, tieBreak: byInvolvedObject(events)})
+}
+
+// withTieBreak orders elements that are equal according to Interface by tieBreak, both must sort the same slice.
+type withTieBreak struct {
+	sort.Interface
+	tieBreak sort.Interface
+}
+
+func (s withTieBreak) Less(i, j int) bool {
+	if s.Interface.Less(i, j) {
+		return true
+	}
+	if s.Interface.Less(j, i) {
+		return false
+	}
+	return s.tieBreak.Less(i, j)
+}
 
 type byTime []*corev1.Event
 
@@ -14,6 +68,40 @@ func (s byTime) Less(i, j int) bool {
 	return s[i].LastTimestamp.Before(&s[j].LastTimestamp)
 }
 
+type byBestTime struct {
+	events    []*corev1.Event
+	ascending bool
+}
+
+func (s byBestTime) Len() int {
+	return len(s.events)
+}
+func (s byBestTime) Swap(i, j int) {
+	s.events[i], s.events[j] = s.events[j], s.events[i]
+}
+func (s byBestTime) Less(i, j int) bool {
+	ti, tj := EventTimestamp(s.events[i]), EventTimestamp(s.events[j])
+	if ti.IsZero() || tj.IsZero() {
+		return !ti.IsZero() && tj.IsZero()
+	}
+	if s.ascending {
+		return ti.Before(tj)
+	}
+	return tj.Before(ti)
+}
+
+type byFirstTime []*corev1.Event
+
+func (s byFirstTime) Len() int {
+	return len(s)
+}
+func (s byFirstTime) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+func (s byFirstTime) Less(i, j int) bool {
+	return s[i].FirstTimestamp.Before(&s[j].FirstTimestamp)
+}
+
 type byFrequency []*corev1.Event
 
 func (s byFrequency) Len() int {
@@ -25,3 +113,42 @@ func (s byFrequency) Swap(i, j int) {
 func (s byFrequency) Less(i, j int) bool {
 	return s[i].Count < s[j].Count
 }
+
+type byRate []*corev1.Event
+
+func (s byRate) Len() int {
+	return len(s)
+}
+func (s byRate) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+func (s byRate) Less(i, j int) bool {
+	return eventRate(s[i]) < eventRate(s[j])
+}
+
+type byReason []*corev1.Event
+
+func (s byReason) Len() int {
+	return len(s)
+}
+func (s byReason) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+func (s byReason) Less(i, j int) bool {
+	return s[i].Reason < s[j].Reason
+}
+
+type byInvolvedObject []*corev1.Event
+
+func (s byInvolvedObject) Len() int {
+	return len(s)
+}
+func (s byInvolvedObject) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+func (s byInvolvedObject) Less(i, j int) bool {
+	if s[i].InvolvedObject.Namespace != s[j].InvolvedObject.Namespace {
+		return s[i].InvolvedObject.Namespace < s[j].InvolvedObject.Namespace
+	}
+	return s[i].InvolvedObject.Name < s[j].InvolvedObject.Name
+}
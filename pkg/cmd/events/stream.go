@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EventStreamFilter filters events as they arrive instead of holding every event in memory.  The returned channel is
+// closed once the input channel is closed and drained, or once ctx is done, so a consumer that stops reading cancels
+// ctx rather than leaving the filter blocked on a send.
+type EventStreamFilter interface {
+	Filter(ctx context.Context, in <-chan *corev1.Event) <-chan *corev1.Event
+}
+
+type EventStreamFilters []EventStreamFilter
+
+func (f EventStreamFilters) Filter(ctx context.Context, in <-chan *corev1.Event) <-chan *corev1.Event {
+	out := in
+	for _, filter := range f {
+		out = filter.Filter(ctx, out)
+	}
+	return out
+}
+
+// sendEvent sends the event to out, it returns false when ctx is done first.
+func sendEvent(ctx context.Context, out chan<- *corev1.Event, event *corev1.Event) bool {
+	select {
+	case out <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// receiveEvent receives an event from in, it returns false when in is closed or ctx is done first.
+func receiveEvent(ctx context.Context, in <-chan *corev1.Event) (*corev1.Event, bool) {
+	select {
+	case event, ok := <-in:
+		return event, ok
+	case <-ctx.Done():
+		return nil, false
+	}
+}
+
+// StreamFilter adapts an EventFilter by passing each event through it on its own, which keeps memory constant.  It is
+// only correct for filters that judge every event independently, like FilterByWarnings or FilterByNamespaces.  Filters
+// that are an EventPredicate are asked about each event directly.
+func StreamFilter(filter EventFilter) EventStreamFilter {
+	return &streamFilter{filter: filter}
+}
+
+type streamFilter struct {
+	filter EventFilter
+}
+
+func (f *streamFilter) Filter(ctx context.Context, in <-chan *corev1.Event) <-chan *corev1.Event {
+	out := make(chan *corev1.Event)
+	go func() {
+		defer close(out)
+		predicate, isPredicate := f.filter.(EventPredicate)
+		for {
+			event, ok := receiveEvent(ctx, in)
+			if !ok {
+				return
+			}
+			if isPredicate {
+				if predicate.Accept(event) && !sendEvent(ctx, out, event) {
+					return
+				}
+				continue
+			}
+			for _, accepted := range f.filter.FilterEvents(event) {
+				if !sendEvent(ctx, out, accepted) {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// BufferedStreamFilter adapts an EventFilter that has to see every event at once, like FilterByAround or
+// FilterByDedup, by collecting the whole input before filtering it.
+func BufferedStreamFilter(filter EventFilter) EventStreamFilter {
+	return &bufferedStreamFilter{filter: filter}
+}
+
+type bufferedStreamFilter struct {
+	filter EventFilter
+}
+
+func (f *bufferedStreamFilter) Filter(ctx context.Context, in <-chan *corev1.Event) <-chan *corev1.Event {
+	out := make(chan *corev1.Event)
+	go func() {
+		defer close(out)
+		events := []*corev1.Event{}
+		for {
+			event, ok := receiveEvent(ctx, in)
+			if !ok {
+				break
+			}
+			events = append(events, event)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		for _, accepted := range f.filter.FilterEvents(events...) {
+			if !sendEvent(ctx, out, accepted) {
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// sendEvents sends the events to a new channel, which is closed after the last one or once ctx is done.
+func sendEvents(ctx context.Context, events []*corev1.Event) <-chan *corev1.Event {
+	in := make(chan *corev1.Event)
+	go func() {
+		defer close(in)
+		for _, event := range events {
+			if !sendEvent(ctx, in, event) {
+				return
+			}
+		}
+	}()
+	return in
+}
+
+func TestEventStreamFilters(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []*corev1.Event{
+		testEvent("ns", "a", "BackOff", base),
+		testEvent("other", "b", "BackOff", base),
+		testEvent("ns", "a", "BackOff", base.Add(time.Minute)),
+		testEvent("ns", "c", "FailedMount", base),
+	}
+	filters := EventStreamFilters{
+		StreamFilter(&FilterByNamespaces{Namespaces: sets.NewString("ns")}),
+		BufferedStreamFilter(&FilterByDedup{}),
+	}
+	want := EventFilters{&FilterByNamespaces{Namespaces: sets.NewString("ns")}, &FilterByDedup{}}.FilterEvents(events...)
+
+	got := []*corev1.Event{}
+	for event := range filters.Filter(context.Background(), sendEvents(context.Background(), events)) {
+		got = append(got, event)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", eventNames(want), eventNames(got))
+	}
+}
+
+func TestEventStreamFiltersStopWhenCancelled(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// the input is never closed, like a watch, so only the context can stop the filters
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan *corev1.Event)
+	go func() {
+		for {
+			if !sendEvent(ctx, in, testEvent("ns", "a", "BackOff", base)) {
+				return
+			}
+		}
+	}()
+	filters := EventStreamFilters{
+		StreamFilter(&FilterByWarnings{}),
+		StreamFilter(&FilterByNamespaces{Namespaces: sets.NewString("ns")}),
+		BufferedStreamFilter(&FilterByDedup{}),
+	}
+	out := filters.Filter(ctx, in)
+
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Errorf("expected no event once the context is done")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the output to be closed once the context is done")
+	}
+}
+
+// filterOnly hides the Accept of a predicate, so it is applied through FilterEvents.
+type filterOnly struct {
+	filter EventFilter
+}
+
+func (f filterOnly) FilterEvents(events ...*corev1.Event) []*corev1.Event {
+	return f.filter.FilterEvents(events...)
+}
+
+func BenchmarkStreamFilter(b *testing.B) {
+	events := benchmarkEvents(10000)
+
+	run := func(b *testing.B, filters EventFilters) {
+		stream := EventStreamFilters{}
+		for _, filter := range filters {
+			stream = append(stream, StreamFilter(filter))
+		}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ctx := context.Background()
+			for range stream.Filter(ctx, sendEvents(ctx, events)) {
+			}
+		}
+	}
+
+	// predicates are asked about each event without building a slice for it
+	b.Run("predicates", func(b *testing.B) {
+		run(b, benchmarkFilters())
+	})
+	b.Run("filters", func(b *testing.B) {
+		filters := EventFilters{}
+		for _, filter := range benchmarkFilters() {
+			filters = append(filters, filterOnly{filter: filter})
+		}
+		run(b, filters)
+	})
+}
@@ -0,0 +1,198 @@
+package events
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// SummaryEntry is the total count of the events sharing a key.
+type SummaryEntry struct {
+	Key   string
+	Total int
+}
+
+// SummarizeBy totals the count of the events by the key returned for each of them.
+func SummarizeBy(events []*corev1.Event, key func(event *corev1.Event) string) map[string]int {
+	summary := map[string]int{}
+	for _, event := range events {
+		summary[key(event)] += eventCount(event)
+	}
+	return summary
+}
+
+func SummarizeByReason(events []*corev1.Event) map[string]int {
+	return SummarizeBy(events, func(event *corev1.Event) string { return event.Reason })
+}
+
+func SummarizeByNamespace(events []*corev1.Event) map[string]int {
+	return SummarizeBy(events, func(event *corev1.Event) string { return event.InvolvedObject.Namespace })
+}
+
+func SummarizeByKind(events []*corev1.Event) map[string]int {
+	return SummarizeBy(events, func(event *corev1.Event) string { return event.InvolvedObject.Kind })
+}
+
+func SummarizeByComponent(events []*corev1.Event) map[string]int {
+	return SummarizeBy(events, eventComponent)
+}
+
+// SummarizeByMessage totals the count of the events by message as normalized by NormalizeMessage, so messages that only
+// differ in names, addresses or numbers are counted together.
+func SummarizeByMessage(events []*corev1.Event) map[string]int {
+	return SummarizeBy(events, func(event *corev1.Event) string { return NormalizeMessage(event.Message) })
+}
+
+// Summarize totals the count of the events by reason, namespace, kind, component or normalized message.
+func Summarize(events []*corev1.Event, groupBy string) (map[string]int, error) {
+	switch groupBy {
+	case "reason":
+		return SummarizeByReason(events), nil
+	case "namespace":
+		return SummarizeByNamespace(events), nil
+	case "kind":
+		return SummarizeByKind(events), nil
+	case "component":
+		return SummarizeByComponent(events), nil
+	case "message":
+		return SummarizeByMessage(events), nil
+	default:
+		return nil, fmt.Errorf("unsupported group by %q, must be one of reason, namespace, kind, component or message", groupBy)
+	}
+}
+
+// SummarizeByComponentAndReason totals the count of the events by reason for each reporting component.
+func SummarizeByComponentAndReason(events []*corev1.Event) map[string]map[string]int {
+	summary := map[string]map[string]int{}
+	for _, event := range events {
+		component := eventComponent(event)
+		if summary[component] == nil {
+			summary[component] = map[string]int{}
+		}
+		summary[component][event.Reason] += eventCount(event)
+	}
+	return summary
+}
+
+// SortSummary orders a summary by descending total, then by key.
+func SortSummary(summary map[string]int) []SummaryEntry {
+	entries := make([]SummaryEntry, 0, len(summary))
+	for key, total := range summary {
+		entries = append(entries, SummaryEntry{Key: key, Total: total})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Total != entries[j].Total {
+			return entries[i].Total > entries[j].Total
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// ObjectEventCount is the total count of the events about one involved object.
+type ObjectEventCount struct {
+	Object corev1.ObjectReference
+	Total  int
+	// Reasons is the number of distinct reasons of the events about the object
+	Reasons int
+}
+
+// TopInvolvedObjects returns the n involved objects with the highest total count of events, ordered by descending
+// total, then by namespace, kind and name.  Objects are identified by namespace, kind and name, n <= 0 returns all.
+func TopInvolvedObjects(events []*corev1.Event, n int) []ObjectEventCount {
+	counts := map[string]*ObjectEventCount{}
+	reasons := map[string]sets.String{}
+	for _, event := range events {
+		key := involvedObjectKey(event.InvolvedObject)
+		if counts[key] == nil {
+			counts[key] = &ObjectEventCount{Object: event.InvolvedObject}
+			reasons[key] = sets.NewString()
+		}
+		counts[key].Total += eventCount(event)
+		reasons[key].Insert(event.Reason)
+	}
+
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		counts[key].Reasons = reasons[key].Len()
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]].Total != counts[keys[j]].Total {
+			return counts[keys[i]].Total > counts[keys[j]].Total
+		}
+		return keys[i] < keys[j]
+	})
+	if n > 0 && n < len(keys) {
+		keys = keys[:n]
+	}
+
+	ret := make([]ObjectEventCount, 0, len(keys))
+	for _, key := range keys {
+		ret = append(ret, *counts[key])
+	}
+	return ret
+}
+
+// UniqueInvolvedObjects returns every distinct involved object with the total count of its events, ordered by kind,
+// namespace and name, then UID.  Objects are identified by UID when the events have one, so an object deleted and
+// created again is listed twice, and by kind, namespace and name otherwise.
+func UniqueInvolvedObjects(events []*corev1.Event) []ObjectEventCount {
+	counts := map[string]*ObjectEventCount{}
+	reasons := map[string]sets.String{}
+	for _, event := range events {
+		key := string(event.InvolvedObject.UID)
+		if len(key) == 0 {
+			key = involvedObjectKey(event.InvolvedObject)
+		}
+		if counts[key] == nil {
+			counts[key] = &ObjectEventCount{Object: event.InvolvedObject}
+			reasons[key] = sets.NewString()
+		}
+		counts[key].Total += eventCount(event)
+		reasons[key].Insert(event.Reason)
+	}
+
+	ret := make([]ObjectEventCount, 0, len(counts))
+	for key, count := range counts {
+		count.Reasons = reasons[key].Len()
+		ret = append(ret, *count)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		objI, objJ := ret[i].Object, ret[j].Object
+		if objI.Kind != objJ.Kind {
+			return objI.Kind < objJ.Kind
+		}
+		if objI.Namespace != objJ.Namespace {
+			return objI.Namespace < objJ.Namespace
+		}
+		if objI.Name != objJ.Name {
+			return objI.Name < objJ.Name
+		}
+		return objI.UID < objJ.UID
+	})
+	return ret
+}
+
+// involvedObjectKey identifies an involved object by namespace, kind and name.
+func involvedObjectKey(ref corev1.ObjectReference) string {
+	return ref.Namespace + "/" + ref.Kind + "/" + ref.Name
+}
+
+// eventCount returns the number of times the event was observed, events without a count were observed once.
+func eventCount(event *corev1.Event) int {
+	if event.Count == 0 {
+		return 1
+	}
+	return int(event.Count)
+}
+
+// eventComponent returns the ReportingController of the event, or the legacy Source.Component when it is not set.
+func eventComponent(event *corev1.Event) string {
+	if len(event.ReportingController) > 0 {
+		return event.ReportingController
+	}
+	return event.Source.Component
+}
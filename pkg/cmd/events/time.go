@@ -0,0 +1,226 @@
+package events
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Clock tells the current time to the filters on time relative to now, so they can be given a fixed time.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock of the system.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// clockOrReal returns clock, or the RealClock when it is nil.
+func clockOrReal(clock Clock) Clock {
+	if clock == nil {
+		return RealClock{}
+	}
+	return clock
+}
+
+// clockTime is a time of day, given as HH:MM or HH:MM:SS.
+type clockTime struct {
+	hours   int
+	minutes int
+	seconds int
+}
+
+func parseClockTime(value string) (clockTime, error) {
+	c := clockTime{}
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return c, fmt.Errorf("must be HH:MM or HH:MM:SS, got %q", value)
+	}
+	var err error
+	if c.hours, err = strconv.Atoi(parts[0]); err != nil {
+		return c, fmt.Errorf("error parsing %q: %v", value, err)
+	}
+	if c.minutes, err = strconv.Atoi(parts[1]); err != nil {
+		return c, fmt.Errorf("error parsing %q: %v", value, err)
+	}
+	if len(parts) > 2 {
+		if c.seconds, err = strconv.Atoi(parts[2]); err != nil {
+			return c, fmt.Errorf("error parsing %q: %v", value, err)
+		}
+	}
+	return c, nil
+}
+
+// on returns the time of day on the same day as t, in the location of t.
+func (c clockTime) on(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), c.hours, c.minutes, c.seconds, 0, t.Location())
+}
+
+// before reports whether c is earlier in the day than other.
+func (c clockTime) before(other clockTime) bool {
+	if c.hours != other.hours {
+		return c.hours < other.hours
+	}
+	if c.minutes != other.minutes {
+		return c.minutes < other.minutes
+	}
+	return c.seconds < other.seconds
+}
+
+// parseTimeOrClockTime parses an RFC3339 time or an HH:MM[:SS] time of day, an empty value returns neither.
+func parseTimeOrClockTime(value string) (time.Time, *clockTime, error) {
+	if len(value) == 0 {
+		return time.Time{}, nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil, nil
+	}
+	c, err := parseClockTime(value)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("must be RFC3339, HH:MM or HH:MM:SS, got %q", value)
+	}
+	return time.Time{}, &c, nil
+}
+
+// sinceDaysRegexp matches the day and week units ParseSince accepts on top of those of time.ParseDuration
+var sinceDaysRegexp = regexp.MustCompile(`([0-9]*\.?[0-9]+)([dw])`)
+
+// ParseSince parses a duration like the --since of kubectl logs, e.g. 30m or 1h30m, also accepting days and weeks as
+// d and w, e.g. 2d or 1w.  An empty value or zero duration is zero, which filters nothing.
+func ParseSince(value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	var err error
+	expanded := sinceDaysRegexp.ReplaceAllStringFunc(value, func(match string) string {
+		parts := sinceDaysRegexp.FindStringSubmatch(match)
+		number, parseErr := strconv.ParseFloat(parts[1], 64)
+		if parseErr != nil {
+			err = parseErr
+			return match
+		}
+		hours := number * 24
+		if parts[2] == "w" {
+			hours *= 7
+		}
+		return strconv.FormatFloat(hours, 'f', -1, 64) + "h"
+	})
+	if err != nil {
+		return 0, fmt.Errorf("invalid since duration %q: %v", value, err)
+	}
+	since, err := time.ParseDuration(expanded)
+	if err != nil {
+		return 0, fmt.Errorf("invalid since duration %q, must be a duration like 30m, 1h30m or 2d", value)
+	}
+	if since < 0 {
+		return 0, fmt.Errorf("invalid since duration %q, must not be negative", value)
+	}
+	return since, nil
+}
+
+// EventTimestamp returns the best timestamp of the event, preferring EventTime, then LastTimestamp, then FirstTimestamp.
+// Events created through the events.k8s.io API often only populate EventTime, so the filters and sorts on time go
+// through it rather than reading LastTimestamp.
+func EventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	default:
+		return event.FirstTimestamp.Time
+	}
+}
+
+// latestTimestamp returns the most recent EventTimestamp of the events.
+func latestTimestamp(events []*corev1.Event) time.Time {
+	latest := time.Time{}
+	for _, event := range events {
+		if t := EventTimestamp(event); t.After(latest) {
+			latest = t
+		}
+	}
+	return latest
+}
+
+// EventSpan returns the time between the FirstTimestamp and the LastTimestamp of the event, or zero when either is
+// missing.
+func EventSpan(event *corev1.Event) time.Duration {
+	if event.FirstTimestamp.IsZero() || event.LastTimestamp.IsZero() {
+		return 0
+	}
+	if span := event.LastTimestamp.Sub(event.FirstTimestamp.Time); span > 0 {
+		return span
+	}
+	return 0
+}
+
+// EventInterval returns the average time between two occurrences of the event, or zero when it was observed once or
+// has no span.
+func EventInterval(event *corev1.Event) time.Duration {
+	span := EventSpan(event)
+	if span == 0 || event.Count < 2 {
+		return 0
+	}
+	return span / time.Duration(event.Count-1)
+}
+
+// eventRate returns the number of occurrences of the event per second over its span, or zero when it has no span.
+func eventRate(event *corev1.Event) float64 {
+	span := EventSpan(event)
+	if span == 0 {
+		return 0
+	}
+	return float64(eventCount(event)) / span.Seconds()
+}
+
+// humanDuration formats d like kubectl formats ages: the two most significant units while they are small, e.g. 3m12s
+// or 5h30m, then only the larger one, e.g. 45m or 12d.  It mirrors HumanDuration of k8s.io/apimachinery.
+func humanDuration(d time.Duration) string {
+	// allow a few seconds of clock skew before calling a time in the future invalid
+	if seconds := int(d.Seconds()); seconds < -1 {
+		return "<invalid>"
+	} else if seconds < 0 {
+		return "0s"
+	} else if seconds < 60*2 {
+		return fmt.Sprintf("%ds", seconds)
+	}
+	minutes := int(d / time.Minute)
+	if minutes < 10 {
+		if s := int(d/time.Second) % 60; s != 0 {
+			return fmt.Sprintf("%dm%ds", minutes, s)
+		}
+		return fmt.Sprintf("%dm", minutes)
+	} else if minutes < 60*3 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	hours := int(d / time.Hour)
+	if hours < 8 {
+		if m := int(d/time.Minute) % 60; m != 0 {
+			return fmt.Sprintf("%dh%dm", hours, m)
+		}
+		return fmt.Sprintf("%dh", hours)
+	} else if hours < 48 {
+		return fmt.Sprintf("%dh", hours)
+	} else if hours < 24*8 {
+		if h := hours % 24; h != 0 {
+			return fmt.Sprintf("%dd%dh", hours/24, h)
+		}
+		return fmt.Sprintf("%dd", hours/24)
+	} else if hours < 24*365*2 {
+		return fmt.Sprintf("%dd", hours/24)
+	} else if hours < 24*365*8 {
+		if dy := hours / 24 % 365; dy != 0 {
+			return fmt.Sprintf("%dy%dd", hours/24/365, dy)
+		}
+		return fmt.Sprintf("%dy", hours/24/365)
+	}
+	return fmt.Sprintf("%dy", hours/24/365)
+}
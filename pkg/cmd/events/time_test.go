@@ -0,0 +1,160 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeClock is a Clock stopped at a fixed time.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestEventTimestamp(t *testing.T) {
+	eventTime := time.Date(2020, 1, 1, 12, 0, 3, 0, time.UTC)
+	last := time.Date(2020, 1, 1, 12, 0, 2, 0, time.UTC)
+	first := time.Date(2020, 1, 1, 12, 0, 1, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		eventTime bool
+		last      bool
+		first     bool
+		expected  time.Time
+	}{
+		{name: "all", eventTime: true, last: true, first: true, expected: eventTime},
+		{name: "event time and last", eventTime: true, last: true, expected: eventTime},
+		{name: "event time and first", eventTime: true, first: true, expected: eventTime},
+		{name: "event time", eventTime: true, expected: eventTime},
+		{name: "last and first", last: true, first: true, expected: last},
+		{name: "last", last: true, expected: last},
+		{name: "first", first: true, expected: first},
+		{name: "none", expected: time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := &corev1.Event{}
+			if test.eventTime {
+				event.EventTime = metav1.NewMicroTime(eventTime)
+			}
+			if test.last {
+				event.LastTimestamp = metav1.NewTime(last)
+			}
+			if test.first {
+				event.FirstTimestamp = metav1.NewTime(first)
+			}
+			if got := EventTimestamp(event); !got.Equal(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterByAroundEventTimeOnly(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	near := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "near"}, EventTime: metav1.NewMicroTime(base.Add(30 * time.Second))}
+	far := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "far"}, EventTime: metav1.NewMicroTime(base.Add(time.Hour))}
+
+	filter, err := NewFilterByAround("12:00", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := filter.FilterEvents(near, far)
+	if len(got) != 1 || got[0] != near {
+		t.Errorf("expected only %s, got %v", near.Name, eventNames(got))
+	}
+}
+
+func TestFilterByAgeAndSince(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: now}
+	old := testEvent("ns", "old", "BackOff", now.Add(-2*time.Hour))
+	hour := testEvent("ns", "hour", "BackOff", now.Add(-time.Hour))
+	recent := testEvent("ns", "recent", "BackOff", now.Add(-5*time.Minute))
+	undated := &corev1.Event{ObjectMeta: metav1.ObjectMeta{Name: "undated"}}
+	events := []*corev1.Event{old, hour, recent, undated}
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		expected []*corev1.Event
+	}{
+		{name: "max age", filter: &FilterByAge{MaxAge: time.Hour, Clock: clock}, expected: []*corev1.Event{hour, recent}},
+		{name: "min age", filter: &FilterByAge{MinAge: time.Hour, Clock: clock}, expected: []*corev1.Event{old, hour}},
+		{name: "age range", filter: &FilterByAge{MaxAge: 90 * time.Minute, MinAge: 10 * time.Minute, Clock: clock}, expected: []*corev1.Event{hour}},
+		{name: "max age from the newest event", filter: &FilterByAge{MaxAge: 55 * time.Minute, FromNewest: true, Clock: &fakeClock{now: now.Add(24 * time.Hour)}}, expected: []*corev1.Event{hour, recent}},
+		{name: "since", filter: &FilterBySince{Since: 30 * time.Minute, Clock: clock}, expected: []*corev1.Event{recent}},
+		{name: "since later", filter: &FilterBySince{Since: 30 * time.Minute, Clock: &fakeClock{now: now.Add(time.Hour)}}, expected: []*corev1.Event{}},
+		{name: "zero since", filter: &FilterBySince{Clock: clock}, expected: events},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.filter.FilterEvents(events...); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", eventNames(test.expected), eventNames(got))
+			}
+		})
+	}
+}
+
+func TestFilterByAgeKeepsFutureEvents(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: now}
+	// stamped by a node whose clock runs ahead
+	future := testEvent("ns", "future", "BackOff", now.Add(time.Minute))
+
+	tests := []struct {
+		name     string
+		filter   EventFilter
+		expected []*corev1.Event
+	}{
+		{name: "max age", filter: &FilterByAge{MaxAge: time.Hour, Clock: clock}, expected: []*corev1.Event{future}},
+		{name: "since", filter: &FilterBySince{Since: time.Hour, Clock: clock}, expected: []*corev1.Event{future}},
+		{name: "min age", filter: &FilterByAge{MinAge: time.Second, Clock: clock}, expected: []*corev1.Event{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.filter.FilterEvents(future); !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", eventNames(test.expected), eventNames(got))
+			}
+		})
+	}
+}
+
+func TestFilterByAroundRelativeToNow(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []*corev1.Event{
+		testEvent("ns", "a", "BackOff", now.Add(-21*time.Minute)),
+		testEvent("ns", "b", "BackOff", now.Add(-15*time.Minute)),
+		testEvent("ns", "c", "BackOff", now.Add(-12*time.Minute)),
+		testEvent("ns", "d", "BackOff", now),
+	}
+
+	tests := []struct {
+		arounds  []string
+		now      time.Time
+		expected []string
+	}{
+		{arounds: []string{"now-15m"}, now: now, expected: []string{"b.BackOff", "c.BackOff"}},
+		{arounds: []string{"now"}, now: now, expected: []string{"d.BackOff"}},
+		{arounds: []string{"now-15m/1m"}, now: now, expected: []string{"b.BackOff"}},
+		{arounds: []string{"now-15m", "now"}, now: now, expected: []string{"b.BackOff", "c.BackOff", "d.BackOff"}},
+		{arounds: []string{"now-15m"}, now: now.Add(time.Hour), expected: []string{}},
+	}
+	for _, test := range tests {
+		filters, err := BuildFilters(FilterOptions{Arounds: test.arounds, AroundDuration: 5 * time.Minute, Clock: &fakeClock{now: test.now}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := eventNames(filters.FilterEvents(events...)); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%v at %v: expected %v, got %v", test.arounds, test.now, test.expected, got)
+		}
+	}
+}
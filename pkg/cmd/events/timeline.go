@@ -0,0 +1,118 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// timelineBuckets are the bucket widths RenderTimeline picks from when none is given.
+var timelineBuckets = []time.Duration{
+	time.Second, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour,
+}
+
+// timelineRows is roughly how many rows an automatically sized timeline has.
+const timelineRows = 40
+
+// RenderTimeline writes the number of events by EventTimestamp in each bucket wide interval, from the first to the last
+// event, as a row with the start of the bucket, the number of events and a bar scaled to the busiest bucket.  Empty
+// buckets are written too so gaps stand out.  A bucket <= 0 is picked from the time the events span.
+func RenderTimeline(writer io.Writer, events []*corev1.Event, bucket time.Duration) error {
+	if bucket <= 0 {
+		bucket = timelineBucket(events)
+	}
+	first, last := timelineSpan(events, bucket)
+	return renderTimelineRows(writer, events, bucket, first, last)
+}
+
+// RenderTimelineByReason writes a RenderTimeline for each reason, sharing the bucket and span so they line up.
+func RenderTimelineByReason(writer io.Writer, events []*corev1.Event, bucket time.Duration) error {
+	if bucket <= 0 {
+		bucket = timelineBucket(events)
+	}
+	first, last := timelineSpan(events, bucket)
+
+	byReason := map[string][]*corev1.Event{}
+	for _, event := range events {
+		byReason[event.Reason] = append(byReason[event.Reason], event)
+	}
+	for _, entry := range SortSummary(SummarizeByReason(events)) {
+		if _, err := fmt.Fprintf(writer, "%s:\n", entry.Key); err != nil {
+			return err
+		}
+		if err := renderTimelineRows(writer, byReason[entry.Key], bucket, first, last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderTimelineRows(writer io.Writer, events []*corev1.Event, bucket time.Duration, first, last time.Time) error {
+	if first.IsZero() {
+		return nil
+	}
+
+	counts := map[time.Time]int{}
+	max := 0
+	for _, event := range events {
+		t := EventTimestamp(event)
+		if t.IsZero() {
+			continue
+		}
+		start := t.Truncate(bucket)
+		counts[start]++
+		if counts[start] > max {
+			max = counts[start]
+		}
+	}
+
+	const maxBarWidth = 50
+	for start := first; !start.After(last); start = start.Add(bucket) {
+		count := counts[start]
+		bar := ""
+		if max > 0 {
+			bar = strings.Repeat("#", (count*maxBarWidth+max-1)/max)
+		}
+		if _, err := fmt.Fprintf(writer, "%s %6d %s\n", start.Format("15:04:05"), count, bar); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// timelineSpan returns the start of the first and last buckets holding events, zero when no event has a timestamp.
+func timelineSpan(events []*corev1.Event, bucket time.Duration) (time.Time, time.Time) {
+	first, last := time.Time{}, time.Time{}
+	for _, event := range events {
+		t := EventTimestamp(event)
+		if t.IsZero() {
+			continue
+		}
+		start := t.Truncate(bucket)
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	return first, last
+}
+
+// timelineBucket picks the smallest bucket that fits the span of the events in about timelineRows rows.
+func timelineBucket(events []*corev1.Event) time.Duration {
+	first, last := timelineSpan(events, time.Second)
+	span := last.Sub(first)
+	for _, bucket := range timelineBuckets {
+		if span/bucket <= timelineRows {
+			return bucket
+		}
+	}
+	return timelineBuckets[len(timelineBuckets)-1]
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"strings"
+
+	"github.com/openshift/cluster-debug-tools/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// UnmatchedValuesFilter is a filter of requested values that can report the ones that match no event, to tell a typo
+// from a genuinely empty result.
+type UnmatchedValuesFilter interface {
+	// UnmatchedValues returns what the values select, like "namespace", and the values that match none of the events
+	UnmatchedValues(events []*corev1.Event) (string, []string)
+}
+
+// UnmatchedValues returns the values that match none of the events, by what they select, for every filter that supports
+// it.  Every filter is checked against all the events rather than the ones the filters before it kept, so a value is
+// only reported when no event has it, whatever the other filters match.
+func (f EventFilters) UnmatchedValues(events []*corev1.Event) map[string][]string {
+	ret := map[string][]string{}
+	for _, filter := range f {
+		tracker, ok := filter.(UnmatchedValuesFilter)
+		if !ok {
+			continue
+		}
+		if field, values := tracker.UnmatchedValues(events); len(values) > 0 {
+			ret[field] = append(ret[field], values...)
+		}
+	}
+	return ret
+}
+
+// unmatchedValues returns the values, other than exclusions, that match none of the fields of the events.  With prefix
+// set a value also matches the values it is a prefix of.
+func unmatchedValues(values sets.String, events []*corev1.Event, ignoreCase, prefix bool, fields func(*corev1.Event) []string) []string {
+	patterns := map[string]string{}
+	for _, value := range values.UnsortedList() {
+		if strings.HasPrefix(value, "-") {
+			continue
+		}
+		pattern := value
+		if ignoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if prefix {
+			pattern = strings.TrimSuffix(pattern, "*") + "*"
+		}
+		patterns[value] = pattern
+	}
+
+	for _, event := range events {
+		if len(patterns) == 0 {
+			break
+		}
+		for _, field := range fields(event) {
+			if ignoreCase {
+				field = strings.ToLower(field)
+			}
+			for value, pattern := range patterns {
+				if util.MatchGlob(pattern, field) {
+					delete(patterns, value)
+				}
+			}
+		}
+	}
+
+	return sets.StringKeySet(patterns).List()
+}
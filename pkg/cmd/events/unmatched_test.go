@@ -0,0 +1,60 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestUnmatchedValues(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []*corev1.Event{
+		testEvent("ns", "a", "BackOff", base),
+		testEvent("other", "b", "FailedMount", base),
+	}
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want map[string][]string
+	}{
+		{
+			name: "every value matches",
+			opts: FilterOptions{Reasons: []string{"BackOff"}, Namespaces: []string{"ns", "other"}},
+			want: map[string][]string{},
+		},
+		{
+			name: "a value rejected by an earlier filter still matches",
+			opts: FilterOptions{Reasons: []string{"Nope"}, Namespaces: []string{"ns"}},
+			want: map[string][]string{"reason": {"Nope"}},
+		},
+		{
+			name: "values matched by other events of the same filter",
+			opts: FilterOptions{Reasons: []string{"BackOff"}, Namespaces: []string{"other"}},
+			want: map[string][]string{},
+		},
+		{
+			name: "exclusions are never reported",
+			opts: FilterOptions{Namespaces: []string{"-nope", "nope"}},
+			want: map[string][]string{"namespace": {"nope"}},
+		},
+		{
+			name: "wildcards, case and prefixes",
+			opts: FilterOptions{Reasons: []string{"backoff", "Failed", "Nope*"}, IgnoreCase: true, ReasonPrefix: true, Names: []string{"*b", "c*"}},
+			want: map[string][]string{"reason": {"Nope*"}, "name": {"c*"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filters, err := BuildFilters(test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filters.UnmatchedValues(events); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// WatchEvents lists the events in the namespace, or in every namespace when allNamespaces is set, then watches the
+// cluster and passes every new or updated event to handle until handle fails.  When the watch closes it resumes from
+// the last resource version seen, and when that version is too old it lists the events again, passing only the events
+// that changed since they were last handled.
+func WatchEvents(restClientGetter genericclioptions.RESTClientGetter, namespace string, allNamespaces bool, handle func(event *corev1.Event) error) error {
+	return watchEvents(context.Background(), restClientGetter, namespace, allNamespaces, handle)
+}
+
+// WatchFilteredEvents is WatchEvents sending the events that match the filters to out, until ctx is done or the watch
+// fails, then closes out.  Events are judged one at a time as they arrive, so filters that need every event at once,
+// like FilterByAround, FilterByDedup or FilterByRate, are rejected.
+func WatchFilteredEvents(ctx context.Context, restClientGetter genericclioptions.RESTClientGetter, namespace string, allNamespaces bool, filters EventFilters, out chan<- *corev1.Event) error {
+	defer close(out)
+	if err := checkWatchFilters(filters); err != nil {
+		return err
+	}
+
+	return watchEvents(ctx, restClientGetter, namespace, allNamespaces, func(event *corev1.Event) error {
+		accepted, err := filters.FilterEventsCtx(ctx, event)
+		if err != nil {
+			return err
+		}
+		for _, event := range accepted {
+			select {
+			case out <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+}
+
+// checkWatchFilters returns an error for the filters that can't judge an event on its own.
+func checkWatchFilters(filters []EventFilter) error {
+	for _, filter := range filters {
+		switch f := filter.(type) {
+		case EventFilters:
+			if err := checkWatchFilters(f); err != nil {
+				return err
+			}
+		case *AnyFilter:
+			if err := checkWatchFilters(f.Filters); err != nil {
+				return err
+			}
+		case *NotFilter:
+			if err := checkWatchFilters([]EventFilter{f.Delegate}); err != nil {
+				return err
+			}
+		case *FilterByAround, *FilterByDedup, *FilterByRate, *FilterByObjectSpread:
+			return fmt.Errorf("%T needs every event at once and cannot be used on a watch", filter)
+		case *FilterByTimeRange:
+			if f.startClock != nil || f.endClock != nil {
+				return fmt.Errorf("times of day are taken on the day of the newest event and cannot be used on a watch")
+			}
+		case *FilterByAge:
+			if f.FromNewest {
+				return fmt.Errorf("ages relative to the newest event cannot be used on a watch")
+			}
+		}
+	}
+	return nil
+}
+
+func watchEvents(ctx context.Context, restClientGetter genericclioptions.RESTClientGetter, namespace string, allNamespaces bool, handle func(event *corev1.Event) error) error {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	newResult := func() *resource.Result {
+		return resource.NewBuilder(restClientGetter).
+			WithScheme(scheme, corev1.SchemeGroupVersion).
+			NamespaceParam(namespace).DefaultNamespace().AllNamespaces(allNamespaces).
+			ResourceTypes("events").SelectAllParam(true).
+			Do()
+	}
+
+	handled := handledEvents{}
+	accept := func(event *corev1.Event) error {
+		if !handled.add(event) {
+			return nil
+		}
+		return handle(event)
+	}
+
+	resourceVersion := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if len(resourceVersion) == 0 {
+			listed := sets.NewString()
+			err := newResult().Visit(func(info *resource.Info, err error) error {
+				if err != nil {
+					return err
+				}
+				items, err := meta.ExtractList(info.Object)
+				if err != nil {
+					return err
+				}
+				for _, item := range items {
+					event, ok := item.(*corev1.Event)
+					if !ok {
+						return fmt.Errorf("unhandled resource: %T", item)
+					}
+					listed.Insert(string(event.UID))
+					if err := accept(event); err != nil {
+						return err
+					}
+				}
+				resourceVersion = info.ResourceVersion
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			// the events deleted while not watching can't be listed again
+			handled.retain(listed)
+		}
+
+		var err error
+		resourceVersion, err = watchEventsFrom(ctx, newResult(), resourceVersion, accept, handled.remove)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// watchEventsFrom passes the events changed after resourceVersion to handle, and the deleted ones to deleted, until the
+// watch closes.  It returns the resource version to resume from, which is empty when it is too old and the events have
+// to be listed again.
+func watchEventsFrom(ctx context.Context, result *resource.Result, resourceVersion string, handle func(event *corev1.Event) error, deleted func(event *corev1.Event)) (string, error) {
+	w, err := result.Watch(resourceVersion)
+	if err != nil {
+		if errors.IsResourceExpired(err) || errors.IsGone(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer w.Stop()
+
+	for {
+		var watchEvent watch.Event
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case received, ok := <-w.ResultChan():
+			if !ok {
+				return resourceVersion, nil
+			}
+			watchEvent = received
+		}
+
+		switch watchEvent.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+			event, ok := watchEvent.Object.(*corev1.Event)
+			if !ok {
+				return "", fmt.Errorf("unhandled resource: %T", watchEvent.Object)
+			}
+			resourceVersion = event.ResourceVersion
+			if watchEvent.Type == watch.Deleted {
+				deleted(event)
+				continue
+			}
+			if err := handle(event); err != nil {
+				return "", err
+			}
+		case watch.Error:
+			err := errors.FromObject(watchEvent.Object)
+			if errors.IsResourceExpired(err) || errors.IsGone(err) {
+				return "", nil
+			}
+			return "", err
+		}
+	}
+}
+
+// handledEvents is the resource version of every event passed to handle while watching, so listing again after the
+// watch expired does not repeat them.  Deleted events are removed, so it holds at most the events the cluster keeps,
+// which expire after the event TTL of the API server, an hour by default, rather than growing for as long as the watch.
+type handledEvents map[types.UID]string
+
+// add records the event, it returns false when the same version of the event was already handled.
+func (h handledEvents) add(event *corev1.Event) bool {
+	if version, ok := h[event.UID]; ok && version == event.ResourceVersion {
+		return false
+	}
+	h[event.UID] = event.ResourceVersion
+	return true
+}
+
+// remove forgets a deleted event.
+func (h handledEvents) remove(event *corev1.Event) {
+	delete(h, event.UID)
+}
+
+// retain forgets every event whose UID is not listed.
+func (h handledEvents) retain(listed sets.String) {
+	for uid := range h {
+		if !listed.Has(string(uid)) {
+			delete(h, uid)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestHandledEvents(t *testing.T) {
+	base := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	a, b := testEvent("ns", "a", "BackOff", base), testEvent("ns", "b", "BackOff", base)
+	a.UID, a.ResourceVersion = "a", "1"
+	b.UID, b.ResourceVersion = "b", "2"
+
+	handled := handledEvents{}
+	if !handled.add(a) || !handled.add(b) {
+		t.Fatalf("expected new events to be added")
+	}
+	if handled.add(a) {
+		t.Errorf("expected the same version of an event to be handled once")
+	}
+	updated := a.DeepCopy()
+	updated.ResourceVersion = "3"
+	if !handled.add(updated) {
+		t.Errorf("expected a new version of an event to be handled")
+	}
+
+	handled.remove(b)
+	if len(handled) != 1 {
+		t.Errorf("expected a deleted event to be forgotten, got %v", handled)
+	}
+
+	handled.add(b)
+	handled.retain(sets.NewString("b"))
+	if len(handled) != 1 || handled["b"] != "2" {
+		t.Errorf("expected only the listed events to be kept, got %v", handled)
+	}
+}
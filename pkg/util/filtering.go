@@ -6,23 +6,28 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
+// AcceptString reports whether currValue is allowed.  Values prefixed with "-" exclude a value and "*" matches any
+// sequence of characters, including none, so "openshift-*", "*-operator" and "*" are all valid and "-openshift-*"
+// excludes every value starting with "openshift-".  "\*" matches a literal "*", and a "*" in currValue itself is always
+// literal.  Exclusions take precedence over inclusions, and a set made up only of exclusions allows every value it
+// does not exclude.
 func AcceptString(allowedValues sets.String, currValue string) bool {
 	// check for an anti-match
 	if allowedValues.Has("-" + currValue) {
 		return false
 	}
-	for _, allowedValue := range allowedValues.UnsortedList() {
-		if !strings.HasSuffix(allowedValue, "*") || !strings.HasPrefix(allowedValue, "-") {
+	for allowedValue := range allowedValues {
+		if !strings.Contains(allowedValue, "*") || !strings.HasPrefix(allowedValue, "-") {
 			continue
 		}
-		if strings.HasPrefix("-"+currValue, allowedValue[:len(allowedValue)-1]) {
+		if MatchGlob(allowedValue[1:], currValue) {
 			return false
 		}
 	}
 
 	// if all values are negation, assume * by default
 	allValuesNegative := true
-	for _, allowedValue := range allowedValues.UnsortedList() {
+	for allowedValue := range allowedValues {
 		if !strings.HasPrefix(allowedValue, "-") {
 			allValuesNegative = false
 			break
@@ -35,14 +40,114 @@ func AcceptString(allowedValues sets.String, currValue string) bool {
 	if allowedValues.Has(currValue) {
 		return true
 	}
-	for _, allowedValue := range allowedValues.UnsortedList() {
-		if !strings.HasSuffix(allowedValue, "*") || strings.HasPrefix(allowedValue, "-") {
+	for allowedValue := range allowedValues {
+		if !strings.Contains(allowedValue, "*") || strings.HasPrefix(allowedValue, "-") {
 			continue
 		}
-		if strings.HasPrefix(currValue, allowedValue[:len(allowedValue)-1]) {
+		if MatchGlob(allowedValue, currValue) {
 			return true
 		}
 	}
 
 	return false
 }
+
+// AcceptAnyString reports whether something known by several values, like an event reporting its component in two
+// fields, is allowed.  None of the values may be excluded and, unless allowedValues are all exclusions, one of them must
+// be included.  Empty values are ignored, and with no other values the empty value is checked.
+func AcceptAnyString(allowedValues sets.String, currValues ...string) bool {
+	values := []string{}
+	for _, currValue := range currValues {
+		if len(currValue) > 0 {
+			values = append(values, currValue)
+		}
+	}
+	if len(values) == 0 {
+		values = append(values, "")
+	}
+
+	exclusions := sets.NewString()
+	for _, allowedValue := range allowedValues.UnsortedList() {
+		if strings.HasPrefix(allowedValue, "-") {
+			exclusions.Insert(allowedValue)
+		}
+	}
+	for _, value := range values {
+		if !AcceptString(exclusions, value) {
+			return false
+		}
+	}
+	for _, value := range values {
+		if AcceptString(allowedValues, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptStringIgnoreCase is AcceptString comparing values regardless of case.  Callers checking many values against
+// the same set should lower it once with LowerStrings and use AcceptString instead.
+func AcceptStringIgnoreCase(allowedValues sets.String, currValue string) bool {
+	return AcceptString(LowerStrings(allowedValues), strings.ToLower(currValue))
+}
+
+// LowerStrings returns the values in lower case.
+func LowerStrings(values sets.String) sets.String {
+	ret := sets.NewString()
+	for _, value := range values.UnsortedList() {
+		ret.Insert(strings.ToLower(value))
+	}
+	return ret
+}
+
+// MatchGlob reports whether value matches pattern, where "*" in pattern matches any sequence of characters, including
+// none, and "\*" matches a literal "*".  No other characters are special.
+func MatchGlob(pattern, value string) bool {
+	parts := splitGlob(pattern)
+	if len(parts) == 1 {
+		return parts[0] == value
+	}
+
+	// the first and last parts are anchored to the start and end of the value
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := parts[len(parts)-1]
+	if len(value) < len(last) || !strings.HasSuffix(value, last) {
+		return false
+	}
+	value = value[:len(value)-len(last)]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+
+	return true
+}
+
+// splitGlob splits pattern at every "*" that is not escaped as "\*", the escaped ones are literal in the parts.
+func splitGlob(pattern string) []string {
+	if !strings.Contains(pattern, `\*`) {
+		return strings.Split(pattern, "*")
+	}
+	parts := []string{}
+	curr := strings.Builder{}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern) && pattern[i+1] == '*':
+			curr.WriteByte('*')
+			i++
+		case pattern[i] == '*':
+			parts = append(parts, curr.String())
+			curr.Reset()
+		default:
+			curr.WriteByte(pattern[i])
+		}
+	}
+	return append(parts, curr.String())
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "openshift-*", value: "openshift-etcd", want: true},
+		{pattern: "openshift-*", value: "openshift-", want: true},
+		{pattern: "openshift-*", value: "openshift", want: false},
+		{pattern: "openshift-*", value: "kube-openshift-etcd", want: false},
+		{pattern: "*-operator", value: "openshift-etcd-operator", want: true},
+		{pattern: "*-operator", value: "-operator", want: true},
+		{pattern: "*-operator", value: "openshift-operators", want: false},
+		{pattern: "*", value: "anything", want: true},
+		{pattern: "*", value: "", want: true},
+		{pattern: "a*b*c", value: "abc", want: true},
+		{pattern: "a*b*c", value: "a-b-b-c", want: true},
+		{pattern: "a*b*c", value: "acb", want: false},
+		{pattern: "ab*ba", value: "aba", want: false},
+		{pattern: "exact", value: "exact", want: true},
+		{pattern: "exact", value: "exactly", want: false},
+		{pattern: `\*`, value: "*", want: true},
+		{pattern: `\*`, value: "anything", want: false},
+		{pattern: `a\*b`, value: "a*b", want: true},
+		{pattern: `a\*b`, value: "axb", want: false},
+		{pattern: `a\**`, value: "a*bc", want: true},
+		{pattern: `a\**`, value: "abc", want: false},
+		{pattern: `a\b`, value: `a\b`, want: true},
+	}
+	for _, test := range tests {
+		if got := MatchGlob(test.pattern, test.value); got != test.want {
+			t.Errorf("MatchGlob(%q, %q): expected %v, got %v", test.pattern, test.value, test.want, got)
+		}
+	}
+}
+
+func TestAcceptString(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		value   string
+		want    bool
+	}{
+		{name: "exact", allowed: []string{"kube-system"}, value: "kube-system", want: true},
+		{name: "exact other", allowed: []string{"kube-system"}, value: "default", want: false},
+		{name: "prefix glob", allowed: []string{"openshift-*"}, value: "openshift-etcd", want: true},
+		{name: "prefix glob other", allowed: []string{"openshift-*"}, value: "kube-system", want: false},
+		{name: "suffix glob", allowed: []string{"*-operator"}, value: "openshift-etcd-operator", want: true},
+		{name: "suffix glob other", allowed: []string{"*-operator"}, value: "openshift-etcd", want: false},
+		{name: "everything", allowed: []string{"*"}, value: "anything", want: true},
+		{name: "everything empty", allowed: []string{"*"}, value: "", want: true},
+		{name: "exclusion", allowed: []string{"-kube-system"}, value: "kube-system", want: false},
+		{name: "only exclusions allow the rest", allowed: []string{"-kube-system"}, value: "default", want: true},
+		{name: "glob exclusion", allowed: []string{"-openshift-*"}, value: "openshift-etcd", want: false},
+		{name: "glob exclusion allows the rest", allowed: []string{"-openshift-*"}, value: "default", want: true},
+		{name: "exclusion beats glob", allowed: []string{"openshift-*", "-openshift-etcd"}, value: "openshift-etcd", want: false},
+		{name: "glob exclusion beats exact", allowed: []string{"openshift-etcd", "-openshift-*"}, value: "openshift-etcd", want: false},
+		{name: "everything but", allowed: []string{"*", "-*-operator"}, value: "openshift-etcd-operator", want: false},
+		{name: "literal star", allowed: []string{`\*`}, value: "*", want: true},
+		{name: "literal star is not a glob", allowed: []string{`\*`}, value: "anything", want: false},
+		{name: "star in value is literal", allowed: []string{"a-b"}, value: "a*", want: false},
+		{name: "excluded literal star", allowed: []string{`-\*`}, value: "*", want: false},
+		{name: "excluded literal star allows the rest", allowed: []string{`-\*`}, value: "anything", want: true},
+	}
+	for _, test := range tests {
+		if got := AcceptString(sets.NewString(test.allowed...), test.value); got != test.want {
+			t.Errorf("%s: AcceptString(%v, %q): expected %v, got %v", test.name, test.allowed, test.value, test.want, got)
+		}
+	}
+}